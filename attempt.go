@@ -0,0 +1,113 @@
+package backoff
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ContextWithAttempt returns a copy of ctx carrying a. It is used by
+// RetryContext for the context of each attempt, and can be used to call a
+// ContextOperation outside of a retry loop.
+func ContextWithAttempt(ctx context.Context, a Attempt) context.Context {
+	return context.WithValue(ctx, attemptKey{}, a)
+}
+
+// WithAttemptID generates an ID with gen for each attempt, available in
+// Attempt.ID, so that downstream logs can be correlated to attempts.
+// NewAttemptID can be used as gen.
+func WithAttemptID(gen func() string) RetryOption {
+	return func(o *retryOptions) { o.attemptID = gen }
+}
+
+// NewAttemptID returns a random 16 character hexadecimal ID.
+func NewAttemptID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// WithAttemptContext calls f to derive the context of each attempt of a
+// ContextOperation, for example to start a trace span per attempt. f
+// receives the context already carrying the attempt, and returns the
+// context to use together with a function called when the attempt returns.
+func WithAttemptContext(f func(ctx context.Context, a Attempt) (context.Context, func(err error))) RetryOption {
+	return func(o *retryOptions) { o.attemptContexts = append(o.attemptContexts, f) }
+}
+
+// WithAttemptTimeout sets the timeout of each attempt of a ContextOperation
+// to timeout(next), next being the delay the retry loop will wait if the
+// attempt fails, or Stop for the last attempt. Early attempts can thus fail
+// fast while later ones get more time. CappedTimeout and
+// ProportionalTimeout build common timeout functions.
+//
+// The BackOff is asked for the delay before each attempt instead of after
+// it, including before an attempt that succeeds. The number of attempts is
+// unchanged, e.g. WithMaxRetries(b, 3) still allows 4 attempts, but the
+// policy advances once more than without a timeout when the operation
+// succeeds, which decorators recording the intervals, such as WithStats,
+// see as an interval that was never waited. next is zero with policies such
+// as ZeroBackOff, so timeout functions should return a minimum.
+func WithAttemptTimeout(timeout func(next time.Duration) time.Duration) RetryOption {
+	return func(o *retryOptions) { o.attemptTimeout = timeout }
+}
+
+// CappedTimeout returns a timeout function for WithAttemptTimeout that
+// gives each attempt as much time as the next delay, between min and max.
+// The last attempt gets max.
+//
+// min keeps attempts possible when the delays are short or zero, as with
+// ZeroBackOff, which would otherwise give every attempt a zero timeout.
+func CappedTimeout(min, max time.Duration) func(next time.Duration) time.Duration {
+	return ProportionalTimeout(1, min, max)
+}
+
+// ProportionalTimeout returns a timeout function for WithAttemptTimeout that
+// gives each attempt factor times the next delay, between min and max. The
+// last attempt gets max. See CappedTimeout for the use of min.
+func ProportionalTimeout(factor float64, min, max time.Duration) func(next time.Duration) time.Duration {
+	return func(next time.Duration) time.Duration {
+		if next == Stop {
+			return max
+		}
+		d := scaleDuration(next, factor)
+		if d > max {
+			d = max
+		}
+		if d < min {
+			d = min
+		}
+		return d
+	}
+}
+
+// attemptContext derives the context of attempt a from the context of the
+// retry loop, next being the delay after the attempt if it is known. The
+// returned function must be called when the attempt returns.
+func (o *retryOptions) attemptContext(ctx context.Context, a Attempt, next time.Duration) (context.Context, func(error)) {
+	ctx = ContextWithAttempt(ctx, a)
+	cancel := func() {}
+	if o.attemptTimeout != nil {
+		ctx, cancel = context.WithTimeout(ctx, o.attemptTimeout(next))
+	}
+	if len(o.attemptContexts) == 0 {
+		return ctx, func(error) { cancel() }
+	}
+
+	ends := make([]func(error), 0, len(o.attemptContexts)+1)
+	ends = append(ends, func(error) { cancel() })
+	for _, f := range o.attemptContexts {
+		var end func(error)
+		ctx, end = f(ctx, a)
+		ends = append(ends, end)
+	}
+	return ctx, func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+	}
+}
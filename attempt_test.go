@@ -0,0 +1,137 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type spanKey struct{}
+
+func TestAttemptContext(t *testing.T) {
+	var ids = 0
+	gen := func() string {
+		ids++
+		return fmt.Sprintf("id-%d", ids)
+	}
+
+	var events []string
+	span := func(ctx context.Context, a Attempt) (context.Context, func(error)) {
+		events = append(events, "start span "+a.ID)
+		return context.WithValue(ctx, spanKey{}, a.Number), func(err error) {
+			events = append(events, fmt.Sprintf("end span %s: %v", a.ID, err))
+		}
+	}
+
+	f := func(ctx context.Context) error {
+		a, _ := AttemptFromContext(ctx)
+		events = append(events, fmt.Sprintf("attempt %s span %v", a.ID, ctx.Value(spanKey{})))
+		if a.Number == 2 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	err := RetryContext(context.Background(), f, &ZeroBackOff{}, WithAttemptID(gen), WithAttemptContext(span))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	expected := []string{
+		"start span id-1", "attempt id-1 span 1", "end span id-1: error",
+		"start span id-2", "attempt id-2 span 2", "end span id-2: <nil>",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("invalid events: %q", events)
+	}
+}
+
+func TestNewAttemptID(t *testing.T) {
+	id := NewAttemptID()
+	if len(id) != 16 || id == NewAttemptID() {
+		t.Errorf("invalid id: %s", id)
+	}
+}
+
+func TestWithAttemptTimeout(t *testing.T) {
+	var timeouts []time.Duration
+	f := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("attempt has no deadline")
+		}
+		// Round to the timeouts used below.
+		timeouts = append(timeouts, (deadline.Sub(time.Now())+5*time.Millisecond)/(10*time.Millisecond)*10*time.Millisecond)
+		return errors.New("error")
+	}
+
+	b := scripted(10*time.Millisecond, 20*time.Millisecond, 100*time.Millisecond)
+	RetryContext(context.Background(), f, b, WithAttemptTimeout(CappedTimeout(5*time.Millisecond, 50*time.Millisecond)))
+
+	expected := "[10ms 20ms 50ms 50ms]"
+	if fmt.Sprint(timeouts) != expected {
+		t.Errorf("got %v, expected %s", timeouts, expected)
+	}
+}
+
+func TestWithAttemptTimeoutMaxRetries(t *testing.T) {
+	var nexts []time.Duration
+	attempts := 0
+	f := func(context.Context) error {
+		attempts++
+		if attempts < 4 {
+			return errors.New("error")
+		}
+		return nil
+	}
+
+	// The last allowed attempt is made with the Stop delay and can succeed.
+	b := WithMaxRetries(&ZeroBackOff{}, 3)
+	err := RetryContext(context.Background(), f, b, WithAttemptTimeout(func(next time.Duration) time.Duration {
+		nexts = append(nexts, next)
+		return time.Second
+	}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if attempts != 4 {
+		t.Errorf("invalid number of attempts: %d", attempts)
+	}
+	expected := "[0s 0s 0s -1ns]"
+	if fmt.Sprint(nexts) != expected {
+		t.Errorf("got %v, expected %s", nexts, expected)
+	}
+}
+
+func TestProportionalTimeout(t *testing.T) {
+	timeout := ProportionalTimeout(2, 100*time.Millisecond, time.Minute)
+	assertEquals(t, 2*time.Second, timeout(time.Second))
+	assertEquals(t, time.Minute, timeout(time.Hour))
+	assertEquals(t, time.Minute, timeout(Stop))
+	assertEquals(t, 100*time.Millisecond, timeout(0))
+	assertEquals(t, 100*time.Millisecond, timeout(10*time.Millisecond))
+}
+
+func TestCappedTimeoutZeroBackOff(t *testing.T) {
+	attempts := 0
+	f := func(ctx context.Context) error {
+		if attempts++; attempts < 3 {
+			return errors.New("error")
+		}
+		// The attempt has the minimum timeout to complete.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+			return nil
+		}
+	}
+
+	err := RetryContext(context.Background(), f, &ZeroBackOff{}, WithAttemptTimeout(CappedTimeout(time.Second, time.Minute)))
+	if err != nil || attempts != 3 {
+		t.Errorf("unexpected error %v after %d attempts", err, attempts)
+	}
+}
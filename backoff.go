@@ -10,7 +10,10 @@
 // See Examples section below for usage examples.
 package backoff
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // BackOff is a backoff policy for retrying an operation.
 type BackOff interface {
@@ -43,6 +46,8 @@ func (b *ZeroBackOff) Reset() {}
 
 func (b *ZeroBackOff) NextBackOff() time.Duration { return 0 }
 
+func (b *ZeroBackOff) String() string { return "ZeroBackOff" }
+
 // StopBackOff is a fixed backoff policy that always returns backoff.Stop for
 // NextBackOff(), meaning that the operation should never be retried.
 type StopBackOff struct{}
@@ -51,6 +56,8 @@ func (b *StopBackOff) Reset() {}
 
 func (b *StopBackOff) NextBackOff() time.Duration { return Stop }
 
+func (b *StopBackOff) String() string { return "StopBackOff" }
+
 // ConstantBackOff is a backoff policy that always returns the same backoff delay.
 // This is in contrast to an exponential backoff policy,
 // which returns a delay that grows longer as you call NextBackOff() over and over again.
@@ -60,6 +67,9 @@ type ConstantBackOff struct {
 
 func (b *ConstantBackOff) Reset()                     {}
 func (b *ConstantBackOff) NextBackOff() time.Duration { return b.Interval }
+func (b *ConstantBackOff) String() string {
+	return fmt.Sprintf("ConstantBackOff{interval=%v}", b.Interval)
+}
 
 func NewConstantBackOff(d time.Duration) *ConstantBackOff {
 	return &ConstantBackOff{Interval: d}
@@ -0,0 +1,87 @@
+// Package backofftest provides a conformance suite for BackOff
+// implementations, to check that custom policies work with Retry and Ticker.
+//
+//	func TestMyBackOff(t *testing.T) {
+//		backofftest.TestBackOff(t, func() backoff.BackOff {
+//			return NewMyBackOff()
+//		})
+//	}
+package backofftest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// Steps is the number of intervals requested from a policy by each check.
+const Steps = 100
+
+// TestBackOff verifies that the policies returned by newBackOff honor the
+// invariants of the BackOff interface:
+//
+//   - NextBackOff never returns a negative duration other than Stop.
+//   - Once NextBackOff returned Stop, it keeps returning Stop until Reset.
+//   - Reset restarts the schedule: a policy that did not stop immediately
+//     when created does not stop immediately after Reset, and a
+//     deterministic policy repeats the same intervals.
+//   - Reset may be called on a new policy.
+//
+// newBackOff must return a new policy on every call.
+func TestBackOff(t *testing.T, newBackOff func() backoff.BackOff) {
+	t.Helper()
+	for _, v := range check(newBackOff) {
+		t.Error(v)
+	}
+}
+
+func check(newBackOff func() backoff.BackOff) []string {
+	var violations []string
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	b := newBackOff()
+	b.Reset()
+	first := intervals(b)
+	for i, d := range first {
+		if d < 0 && d != backoff.Stop {
+			fail("interval %d is negative: %v", i, d)
+		}
+		if i > 0 && first[i-1] == backoff.Stop && d != backoff.Stop {
+			fail("interval %d is %v after Stop", i, d)
+		}
+	}
+
+	b.Reset()
+	again := intervals(b)
+	if first[0] != backoff.Stop && again[0] == backoff.Stop {
+		fail("policy stops immediately after Reset")
+	}
+
+	// Only deterministic policies are expected to repeat their intervals.
+	other := newBackOff()
+	other.Reset()
+	if reflect.DeepEqual(first, intervals(other)) && !reflect.DeepEqual(first, again) {
+		fail("intervals after Reset differ: got %v, expected %v", again, first)
+	}
+	return violations
+}
+
+// Override replaces the policy registered with name, or registers it, for
+// the duration of the test.
+func Override(t testing.TB, name string, p backoff.Policy) {
+	t.Cleanup(backoff.Override(name, p))
+}
+
+// intervals returns the next Steps intervals of b.
+func intervals(b backoff.BackOff) []time.Duration {
+	d := make([]time.Duration, Steps)
+	for i := range d {
+		d[i] = b.NextBackOff()
+	}
+	return d
+}
@@ -0,0 +1,127 @@
+package backofftest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+func TestPolicies(t *testing.T) {
+	policies := map[string]func() backoff.BackOff{
+		"ZeroBackOff":     func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+		"StopBackOff":     func() backoff.BackOff { return &backoff.StopBackOff{} },
+		"ConstantBackOff": func() backoff.BackOff { return backoff.NewConstantBackOff(time.Second) },
+		"ExponentialBackOff": func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+		"WithMaxRetries": func() backoff.BackOff {
+			return backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 5)
+		},
+		"MinOf": func() backoff.BackOff {
+			return backoff.MinOf(backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 3), backoff.NewConstantBackOff(time.Second))
+		},
+		"WeightedOf": func() backoff.BackOff {
+			return backoff.WeightedOf(
+				backoff.Weighted{Weight: 1, BackOff: backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 10)},
+				backoff.Weighted{Weight: 1, BackOff: backoff.NewConstantBackOff(time.Second)},
+			)
+		},
+		"StopWhen": func() backoff.BackOff {
+			return backoff.StopWhen(backoff.NewConstantBackOff(time.Second), func(s backoff.State) bool {
+				return s.Attempts > 3
+			})
+		},
+		"WithMinInterval": func() backoff.BackOff {
+			return backoff.WithMinInterval(backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 5), time.Second)
+		},
+		"WithStats": func() backoff.BackOff {
+			b, _ := backoff.WithStats(backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5))
+			return b
+		},
+		"WithBlackouts": func() backoff.BackOff {
+			now := time.Now()
+			return backoff.WithBlackouts(backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5),
+				[]backoff.Blackout{{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}})
+		},
+		"WithSpread": func() backoff.BackOff {
+			return backoff.WithSpread(backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5), 4, 1, time.Minute)
+		},
+		"WithHints": func() backoff.BackOff {
+			hints := backoff.NewMemoryHints()
+			hints.Set("key", time.Now().Add(time.Hour))
+			return backoff.WithHints(backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5), hints, "key")
+		},
+		"WithShadow": func() backoff.BackOff {
+			b, _ := backoff.WithShadow(backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5), backoff.NewExponentialBackOff())
+			return b
+		},
+		"Tunable": func() backoff.BackOff {
+			return backoff.NewTunable(backoff.PolicyFunc(func() backoff.BackOff {
+				return backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5)
+			})).New()
+		},
+		"WithMaxCumulativeSleep": func() backoff.BackOff {
+			return backoff.WithMaxCumulativeSleep(backoff.NewConstantBackOff(time.Second), 5*time.Second)
+		},
+	}
+	for name, newBackOff := range policies {
+		if violations := check(newBackOff); len(violations) > 0 {
+			t.Errorf("%s: %v", name, violations)
+		}
+	}
+}
+
+type negativeBackOff struct{}
+
+func (b *negativeBackOff) NextBackOff() time.Duration { return -2 }
+func (b *negativeBackOff) Reset()                     {}
+
+// notResetBackOff stops after 3 intervals and ignores Reset.
+type notResetBackOff struct{ n int }
+
+func (b *notResetBackOff) NextBackOff() time.Duration {
+	if b.n++; b.n > 3 {
+		return backoff.Stop
+	}
+	return time.Second
+}
+
+func (b *notResetBackOff) Reset() {}
+
+// flappingBackOff returns an interval after Stop.
+type flappingBackOff struct{ n int }
+
+func (b *flappingBackOff) NextBackOff() time.Duration {
+	if b.n++; b.n%2 == 0 {
+		return backoff.Stop
+	}
+	return time.Second
+}
+
+func (b *flappingBackOff) Reset() { b.n = 0 }
+
+func TestViolations(t *testing.T) {
+	policies := map[string]func() backoff.BackOff{
+		"negative": func() backoff.BackOff { return &negativeBackOff{} },
+		"reset":    func() backoff.BackOff { return &notResetBackOff{} },
+		"flapping": func() backoff.BackOff { return &flappingBackOff{} },
+	}
+	for name, newBackOff := range policies {
+		if violations := check(newBackOff); len(violations) == 0 {
+			t.Errorf("%s: no violations found", name)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		Override(t, "test", backoff.PolicyFunc(func() backoff.BackOff { return &backoff.ZeroBackOff{} }))
+		if _, ok := backoff.Get("test"); !ok {
+			t.Error("policy is not registered")
+		}
+	})
+	if _, ok := backoff.Get("test"); ok {
+		t.Error("policy is not removed after the test")
+	}
+}
@@ -0,0 +1,157 @@
+package backoff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Blackout is a period of time during which no attempt is made, such as a
+// holiday or a change freeze. It starts at Start and ends before End.
+type Blackout struct {
+	Start, End time.Time
+}
+
+// Contains reports whether t is in the blackout.
+func (w Blackout) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+const (
+	blackoutDate = "2006-01-02"
+	blackoutTime = "2006-01-02T15:04"
+)
+
+// ParseBlackouts reads a schedule of blackouts from r, one per line. Empty
+// lines and text after a # are ignored. A line is a date, a blackout for the
+// whole day, or a range of two dates separated by spaces, which includes
+// both days:
+//
+//	# Holidays
+//	2024-12-25
+//	2024-12-24 2025-01-01  # End of year freeze
+//	2025-03-14T18:00 2025-03-17T06:00
+//
+// A range can also be given as times with minutes, in which case it ends at
+// the second time. Dates and times are in loc, so that days are calendar
+// days of loc even if they are not 24 hours long because of daylight saving
+// time.
+func ParseBlackouts(r io.Reader, loc *time.Location) ([]Blackout, error) {
+	var blackouts []Blackout
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		w, err := parseBlackout(fields, loc)
+		if err != nil {
+			return nil, fmt.Errorf("backoff: blackout line %d: %s", n, err)
+		}
+		blackouts = append(blackouts, w)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return blackouts, nil
+}
+
+func parseBlackout(fields []string, loc *time.Location) (Blackout, error) {
+	if len(fields) > 2 {
+		return Blackout{}, fmt.Errorf("too many fields in %q", strings.Join(fields, " "))
+	}
+	start, err := time.ParseInLocation(blackoutDate, fields[0], loc)
+	if err != nil {
+		return parseBlackoutTimes(fields, loc)
+	}
+	end := start
+	if len(fields) == 2 {
+		if end, err = time.ParseInLocation(blackoutDate, fields[1], loc); err != nil {
+			return Blackout{}, fmt.Errorf("invalid date %q", fields[1])
+		}
+	}
+	// AddDate keeps the wall clock, so the day ends at midnight of loc.
+	w := Blackout{start, end.AddDate(0, 0, 1)}
+	if !w.Start.Before(w.End) {
+		return Blackout{}, fmt.Errorf("%s is before %s", fields[1], fields[0])
+	}
+	return w, nil
+}
+
+func parseBlackoutTimes(fields []string, loc *time.Location) (Blackout, error) {
+	if len(fields) != 2 {
+		return Blackout{}, fmt.Errorf("invalid date %q", fields[0])
+	}
+	var w Blackout
+	var err error
+	if w.Start, err = time.ParseInLocation(blackoutTime, fields[0], loc); err != nil {
+		return Blackout{}, fmt.Errorf("invalid time %q", fields[0])
+	}
+	if w.End, err = time.ParseInLocation(blackoutTime, fields[1], loc); err != nil {
+		return Blackout{}, fmt.Errorf("invalid time %q", fields[1])
+	}
+	if !w.Start.Before(w.End) {
+		return Blackout{}, fmt.Errorf("%s is not after %s", fields[1], fields[0])
+	}
+	return w, nil
+}
+
+/*
+WithBlackouts creates a wrapper around another BackOff, which defers the
+next attempt until the end of the blackout it would fall in, e.g. to respect
+change freezes in batch jobs. Overlapping and adjacent blackouts are
+waited out together. Stop is returned unchanged.
+
+Note: Implementation is not thread-safe.
+*/
+func WithBlackouts(b BackOff, blackouts []Blackout) BackOff {
+	return &backOffBlackout{delegate: b, blackouts: blackouts, clock: SystemClock}
+}
+
+type backOffBlackout struct {
+	delegate  BackOff
+	blackouts []Blackout
+	clock     Clock
+}
+
+func (b *backOffBlackout) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		return Stop
+	}
+	return b.postpone(next)
+}
+
+// postpone extends next until the end of the blackouts it falls in.
+func (b *backOffBlackout) postpone(next time.Duration) time.Duration {
+	now := b.clock.Now()
+	at := now.Add(next)
+	for deferred := true; deferred; {
+		deferred = false
+		for _, w := range b.blackouts {
+			if w.Contains(at) {
+				at = w.End
+				deferred = true
+			}
+		}
+	}
+	return at.Sub(now)
+}
+
+func (b *backOffBlackout) Reset() {
+	b.delegate.Reset()
+}
+
+func (b *backOffBlackout) String() string {
+	return fmt.Sprintf("WithBlackouts{blackouts=%d}", len(b.blackouts))
+}
+
+func (b *backOffBlackout) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBlackouts(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	schedule := `
+# Holidays
+2024-12-25
+2024-12-24 2025-01-01  # End of year freeze
+
+2024-03-10
+2025-03-14T18:00 2025-03-17T06:00
+`
+	blackouts, err := ParseBlackouts(strings.NewReader(schedule), loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []Blackout{
+		{time.Date(2024, 12, 25, 0, 0, 0, 0, loc), time.Date(2024, 12, 26, 0, 0, 0, 0, loc)},
+		{time.Date(2024, 12, 24, 0, 0, 0, 0, loc), time.Date(2025, 1, 2, 0, 0, 0, 0, loc)},
+		{time.Date(2024, 3, 10, 0, 0, 0, 0, loc), time.Date(2024, 3, 11, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 3, 14, 18, 0, 0, 0, loc), time.Date(2025, 3, 17, 6, 0, 0, 0, loc)},
+	}
+	if len(blackouts) != len(expected) {
+		t.Fatalf("invalid blackouts: %v", blackouts)
+	}
+	for i, w := range blackouts {
+		if !w.Start.Equal(expected[i].Start) || !w.End.Equal(expected[i].End) {
+			t.Errorf("%d: got %v, want %v", i, w, expected[i])
+		}
+	}
+	// The day daylight saving time starts is 23 hours long.
+	if d := blackouts[2].End.Sub(blackouts[2].Start); d != 23*time.Hour {
+		t.Errorf("invalid duration: %s", d)
+	}
+}
+
+func TestParseBlackoutsErrors(t *testing.T) {
+	for _, s := range []string{
+		"2024-13-01",
+		"2024-12-25 2024-12-24",
+		"2024-12-25 2024-12-26 2024-12-27",
+		"2025-03-14T18:00",
+		"2025-03-14T18:00 2025-03-14T17:00",
+		"tomorrow",
+	} {
+		if _, err := ParseBlackouts(strings.NewReader(s), time.UTC); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestWithBlackouts(t *testing.T) {
+	now := time.Date(2024, 12, 23, 23, 0, 0, 0, time.UTC)
+	blackouts := []Blackout{
+		{time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)},
+	}
+	b := WithBlackouts(scripted(time.Minute, 2*time.Hour), blackouts)
+	b.(*backOffBlackout).clock = fixedClock(now)
+
+	// The second attempt falls in the first blackout, which is followed by
+	// the second one.
+	assertSequence(t, b, time.Minute, 73*time.Hour, Stop)
+}
@@ -0,0 +1,127 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Broadcaster fans out the ticks of a single Ticker to any number of
+// subscribers, so that several workers share one pacing schedule and one
+// policy state.
+//
+// Each subscriber channel buffers one tick. Like time.Ticker, a tick is
+// dropped for subscribers that have not received the previous one, so a slow
+// subscriber never delays the others. Subscriber channels are closed when
+// the ticker stops.
+type Broadcaster struct {
+	ticker *Ticker
+	mu     sync.Mutex
+	subs   map[<-chan time.Time]chan time.Time
+	closed bool
+	first  chan struct{}
+	once   sync.Once
+	done   chan struct{}
+}
+
+// NewBroadcaster returns a Broadcaster sending ticks at times specified by b.
+// The schedule starts with the first subscriber, which is guaranteed to
+// receive the first tick.
+func NewBroadcaster(b BackOff) *Broadcaster {
+	c := &Broadcaster{
+		ticker: NewTicker(b),
+		subs:   make(map[<-chan time.Time]chan time.Time),
+		first:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Subscribe returns a new channel receiving the ticks. The channel is
+// already closed if the ticker has stopped.
+func (c *Broadcaster) Subscribe() <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		close(ch)
+	} else {
+		c.subs[ch] = ch
+		c.once.Do(func() { close(c.first) })
+	}
+	return ch
+}
+
+// Unsubscribe stops the delivery of ticks to ch and closes it.
+func (c *Broadcaster) Unsubscribe(ch <-chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sub, ok := c.subs[ch]; ok {
+		delete(c.subs, ch)
+		close(sub)
+	}
+}
+
+// Stop turns off the ticker. All subscriber channels are closed.
+func (c *Broadcaster) Stop() {
+	c.ticker.Stop()
+}
+
+// Done returns a channel that is closed once the ticker has stopped and all
+// subscriber channels are closed.
+func (c *Broadcaster) Done() <-chan struct{} {
+	return c.done
+}
+
+// Start stops the broadcaster when ctx is canceled.
+func (c *Broadcaster) Start(ctx context.Context) {
+	stopOnCancel(ctx, c.Stop, c.done)
+}
+
+// Close stops the broadcaster and waits until all subscriber channels are
+// closed.
+func (c *Broadcaster) Close() error {
+	c.Stop()
+	<-c.done
+	return nil
+}
+
+// Healthy reports whether the ticker is still running.
+func (c *Broadcaster) Healthy() bool {
+	return c.Err() == nil
+}
+
+// Err reports why the ticker stopped, like Ticker.Err.
+func (c *Broadcaster) Err() error {
+	return c.ticker.Err()
+}
+
+func (c *Broadcaster) run() {
+	defer close(c.done)
+
+	select {
+	case <-c.first:
+	case <-c.ticker.Done():
+	}
+
+	for tick := range c.ticker.C {
+		c.mu.Lock()
+		for _, sub := range c.subs {
+			select {
+			case sub <- tick:
+			default:
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	c.closed = true
+	for ch, sub := range c.subs {
+		delete(c.subs, ch)
+		close(sub)
+	}
+	c.mu.Unlock()
+}
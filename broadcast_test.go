@@ -0,0 +1,75 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcaster(t *testing.T) {
+	c := NewBroadcaster(WithMaxRetries(NewConstantBackOff(10*time.Millisecond), 2))
+
+	a := c.Subscribe()
+	b := c.Subscribe()
+
+	var ticksA, ticksB int
+	for a != nil || b != nil {
+		select {
+		case _, ok := <-a:
+			if !ok {
+				a = nil
+				continue
+			}
+			ticksA++
+		case _, ok := <-b:
+			if !ok {
+				b = nil
+				continue
+			}
+			ticksB++
+		}
+	}
+
+	// A tick may be dropped for a subscriber that is not ready, but the
+	// first one is always delivered.
+	if ticksA < 1 || ticksA > 3 || ticksB < 1 || ticksB > 3 {
+		t.Errorf("invalid number of ticks: %d and %d", ticksA, ticksB)
+	}
+
+	<-c.Done()
+	if err := c.Err(); err != ErrBackOffStopped {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, ok := <-c.Subscribe(); ok {
+		t.Error("channel of stopped broadcaster is not closed")
+	}
+}
+
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	c := NewBroadcaster(NewConstantBackOff(time.Millisecond))
+	defer c.Stop()
+
+	a := c.Subscribe()
+	<-a
+	c.Unsubscribe(a)
+	for range a {
+	}
+
+	b := c.Subscribe()
+	if _, ok := <-b; !ok {
+		t.Error("remaining subscriber does not receive ticks")
+	}
+}
+
+func TestBroadcasterStop(t *testing.T) {
+	c := NewBroadcaster(NewConstantBackOff(time.Millisecond))
+	c.Stop()
+
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("broadcaster is not done")
+	}
+	if err := c.Err(); err != ErrTickerStopped {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
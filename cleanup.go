@@ -0,0 +1,22 @@
+package backoff
+
+// WithCleanup calls cleanup with the error of each failed attempt before the
+// retry loop backs off, for example to close half-open connections or delete
+// temporary files left behind by the attempt.
+//
+// If cleanup returns a *PermanentError, the retry loop stops and returns the
+// wrapped error. Other errors are ignored and the loop keeps retrying.
+func WithCleanup(cleanup func(err error) error) RetryOption {
+	return func(o *retryOptions) { o.cleanups = append(o.cleanups, cleanup) }
+}
+
+// cleanup runs the cleanup functions and returns the error of the first one
+// that failed permanently.
+func (o *retryOptions) cleanup(err error) error {
+	for _, f := range o.cleanups {
+		if permanent, ok := f(err).(*PermanentError); ok {
+			return permanent.Err
+		}
+	}
+	return nil
+}
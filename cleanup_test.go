@@ -0,0 +1,48 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithCleanup(t *testing.T) {
+	var cleaned []error
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("error")
+		}
+		return nil
+	}
+	cleanup := func(err error) error {
+		cleaned = append(cleaned, err)
+		return errors.New("ignored")
+	}
+
+	if err := Retry(f, &ZeroBackOff{}, WithCleanup(cleanup)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if len(cleaned) != 2 {
+		t.Errorf("invalid number of cleanups: %d", len(cleaned))
+	}
+}
+
+func TestWithCleanupPermanent(t *testing.T) {
+	attempts := 0
+	f := func() error {
+		attempts++
+		return errors.New("error")
+	}
+	cleanup := func(err error) error {
+		return Permanent(errors.New("cleanup failed"))
+	}
+
+	err := Retry(f, &ZeroBackOff{}, WithCleanup(cleanup))
+	if err == nil || err.Error() != "cleanup failed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("invalid number of attempts: %d", attempts)
+	}
+}
@@ -0,0 +1,91 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+// StopRule tells when a BackOff combining two policies stops.
+type StopRule int
+
+const (
+	// StopFirst stops as soon as either of the policies stops.
+	StopFirst StopRule = iota
+	// StopLast keeps using the remaining policy after one of them stops,
+	// and stops when both have stopped.
+	StopLast
+)
+
+// Combined is a backoff policy that combines the intervals of two policies
+// at each step. It is created with MinOf or MaxOf.
+//
+// Note: Implementation is not thread-safe.
+type Combined struct {
+	// Rule tells when the combined policy stops. It defaults to StopFirst.
+	Rule StopRule
+
+	a, b               BackOff
+	max                bool
+	aStopped, bStopped bool
+}
+
+// MinOf returns a BackOff that waits the smaller of the intervals of a and b
+// at each step.
+func MinOf(a, b BackOff) *Combined {
+	return &Combined{a: a, b: b}
+}
+
+// MaxOf returns a BackOff that waits the larger of the intervals of a and b
+// at each step. For example, it can enforce a minimum interval on an
+// adaptive policy:
+//
+//	b := backoff.MaxOf(adaptive, backoff.NewConstantBackOff(10*time.Second))
+func MaxOf(a, b BackOff) *Combined {
+	return &Combined{a: a, b: b, max: true}
+}
+
+func (c *Combined) NextBackOff() time.Duration {
+	var da, db time.Duration
+	if !c.aStopped {
+		if da = c.a.NextBackOff(); da == Stop {
+			c.aStopped = true
+		}
+	}
+	if !c.bStopped {
+		if db = c.b.NextBackOff(); db == Stop {
+			c.bStopped = true
+		}
+	}
+
+	switch {
+	case c.aStopped && c.bStopped:
+		return Stop
+	case c.aStopped || c.bStopped:
+		if c.Rule == StopFirst {
+			return Stop
+		}
+		if c.aStopped {
+			return db
+		}
+		return da
+	}
+
+	if (da < db) != c.max {
+		return da
+	}
+	return db
+}
+
+func (c *Combined) String() string {
+	name := "MinOf"
+	if c.max {
+		name = "MaxOf"
+	}
+	return fmt.Sprintf("%s{a=%s, b=%s, aStopped=%v, bStopped=%v}", name, DebugState(c.a), DebugState(c.b), c.aStopped, c.bStopped)
+}
+
+func (c *Combined) Reset() {
+	c.aStopped, c.bStopped = false, false
+	c.a.Reset()
+	c.b.Reset()
+}
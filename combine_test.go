@@ -0,0 +1,52 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+// scripted returns a BackOff returning the given intervals, and then Stop.
+func scripted(intervals ...time.Duration) BackOff {
+	return &scriptedBackOff{intervals: intervals}
+}
+
+type scriptedBackOff struct {
+	intervals []time.Duration
+	i         int
+}
+
+func (b *scriptedBackOff) NextBackOff() time.Duration {
+	if b.i >= len(b.intervals) {
+		return Stop
+	}
+	b.i++
+	return b.intervals[b.i-1]
+}
+
+func (b *scriptedBackOff) Reset() { b.i = 0 }
+
+func assertSequence(t *testing.T, b BackOff, expected ...time.Duration) {
+	for i, e := range expected {
+		if d := b.NextBackOff(); d != e {
+			t.Errorf("%d: got %v, expected %v", i, d, e)
+		}
+	}
+}
+
+func TestMinOf(t *testing.T) {
+	b := MinOf(scripted(1, 5, 3), scripted(2, 4))
+	assertSequence(t, b, 1, 4, Stop, Stop)
+
+	b.Reset()
+	b.Rule = StopLast
+	assertSequence(t, b, 1, 4, 3, Stop, Stop)
+}
+
+func TestMaxOf(t *testing.T) {
+	b := MaxOf(scripted(1, 5, 3), NewConstantBackOff(2))
+	assertSequence(t, b, 2, 5, 3, Stop)
+
+	b.Reset()
+	b.Rule = StopLast
+	assertSequence(t, b, 2, 5, 3, 2, 2)
+}
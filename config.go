@@ -0,0 +1,72 @@
+package backoff
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RegisterFlags defines the following flags in fs and returns an
+// ExponentialBackOff configured from them once fs is parsed:
+//
+//	-retry-initial      InitialInterval
+//	-retry-max          MaxInterval
+//	-retry-multiplier   Multiplier
+//	-retry-max-elapsed  MaxElapsedTime
+//
+// The flags default to the default values of ExponentialBackOff.
+// The policy must be Reset after parsing; Retry and NewTicker do so.
+func RegisterFlags(fs *flag.FlagSet) *ExponentialBackOff {
+	b := NewExponentialBackOff()
+	fs.DurationVar(&b.InitialInterval, "retry-initial", b.InitialInterval, "initial interval between retries")
+	fs.DurationVar(&b.MaxInterval, "retry-max", b.MaxInterval, "maximum interval between retries")
+	fs.Float64Var(&b.Multiplier, "retry-multiplier", b.Multiplier, "multiplier of the interval between retries")
+	fs.DurationVar(&b.MaxElapsedTime, "retry-max-elapsed", b.MaxElapsedTime, "stop retrying after this time, 0 to retry forever")
+	return b
+}
+
+// FromEnv returns an ExponentialBackOff configured from the following
+// environment variables, each name being prefixed with prefix:
+//
+//	RETRY_INITIAL      InitialInterval
+//	RETRY_MAX          MaxInterval
+//	RETRY_MULTIPLIER   Multiplier
+//	RETRY_MAX_ELAPSED  MaxElapsedTime
+//
+// Durations are parsed with time.ParseDuration. Unset or empty variables
+// leave the default values in place.
+func FromEnv(prefix string) (*ExponentialBackOff, error) {
+	b := NewExponentialBackOff()
+	durations := []struct {
+		name string
+		d    *time.Duration
+	}{
+		{"RETRY_INITIAL", &b.InitialInterval},
+		{"RETRY_MAX", &b.MaxInterval},
+		{"RETRY_MAX_ELAPSED", &b.MaxElapsedTime},
+	}
+	for _, v := range durations {
+		s := os.Getenv(prefix + v.name)
+		if s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("backoff: invalid %s%s: %s", prefix, v.name, err)
+		}
+		*v.d = d
+	}
+
+	if s := os.Getenv(prefix + "RETRY_MULTIPLIER"); s != "" {
+		m, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("backoff: invalid %sRETRY_MULTIPLIER: %s", prefix, err)
+		}
+		b.Multiplier = m
+	}
+
+	b.Reset()
+	return b, nil
+}
@@ -0,0 +1,49 @@
+package backoff
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	b := RegisterFlags(fs)
+	if b.InitialInterval != DefaultInitialInterval || b.MaxElapsedTime != DefaultMaxElapsedTime {
+		t.Error("defaults are not used")
+	}
+
+	err := fs.Parse([]string{"-retry-initial=1s", "-retry-max=10s", "-retry-multiplier=3", "-retry-max-elapsed=0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.InitialInterval != time.Second || b.MaxInterval != 10*time.Second ||
+		b.Multiplier != 3 || b.MaxElapsedTime != 0 {
+		t.Errorf("flags are not applied: %+v", b)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	os.Setenv("TEST_RETRY_INITIAL", "2s")
+	os.Setenv("TEST_RETRY_MULTIPLIER", "2.5")
+	defer os.Unsetenv("TEST_RETRY_INITIAL")
+	defer os.Unsetenv("TEST_RETRY_MULTIPLIER")
+
+	b, err := FromEnv("TEST_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.InitialInterval != 2*time.Second || b.Multiplier != 2.5 || b.MaxInterval != DefaultMaxInterval {
+		t.Errorf("environment is not applied: %+v", b)
+	}
+	if b.currentInterval != 2*time.Second {
+		t.Error("policy is not reset")
+	}
+
+	os.Setenv("TEST_RETRY_MAX", "forever")
+	defer os.Unsetenv("TEST_RETRY_MAX")
+	if _, err = FromEnv("TEST_"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
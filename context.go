@@ -1,6 +1,7 @@
 package backoff
 
 import (
+	"fmt"
 	"time"
 
 	"golang.org/x/net/context"
@@ -39,6 +40,42 @@ func WithContext(b BackOff, ctx context.Context) BackOffContext {
 	}
 }
 
+// NewExponentialBackOffForDeadline creates an instance of ExponentialBackOff
+// whose schedule fits in the time remaining until the deadline of ctx.
+//
+// MaxInterval is at most a quarter of the remaining time and MaxElapsedTime
+// leaves room for the longest randomized interval, so that the policy stops
+// instead of sleeping past the deadline. If ctx has no deadline, the default
+// values are used. The returned policy is not bound to ctx; use WithContext
+// to stop retrying when ctx is canceled.
+func NewExponentialBackOffForDeadline(ctx context.Context) *ExponentialBackOff {
+	b := NewExponentialBackOff()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return b
+	}
+
+	remaining := deadline.Sub(b.Clock.Now())
+	if remaining <= 0 {
+		b.InitialInterval = 0
+		b.MaxInterval = 0
+		b.MaxElapsedTime = time.Nanosecond
+		b.Reset()
+		return b
+	}
+
+	if b.MaxInterval > remaining/4 {
+		b.MaxInterval = remaining / 4
+	}
+	if b.InitialInterval > remaining/20 {
+		b.InitialInterval = remaining / 20
+	}
+	longest := SaturatingMul(b.MaxInterval, 1+b.RandomizationFactor)
+	b.MaxElapsedTime = remaining - longest
+	b.Reset()
+	return b
+}
+
 func ensureContext(b BackOff) BackOffContext {
 	if cb, ok := b.(BackOffContext); ok {
 		return cb
@@ -50,6 +87,14 @@ func (b *backOffContext) Context() context.Context {
 	return b.ctx
 }
 
+func (b *backOffContext) String() string {
+	return fmt.Sprintf("WithContext{err=%v}", b.ctx.Err())
+}
+
+func (b *backOffContext) Unwrap() BackOff {
+	return b.BackOff
+}
+
 func (b *backOffContext) NextBackOff() time.Duration {
 	select {
 	case <-b.Context().Done():
@@ -24,3 +24,44 @@ func TestContext(t *testing.T) {
 		t.Error("invalid next back off")
 	}
 }
+
+func TestNewExponentialBackOffForDeadline(t *testing.T) {
+	b := NewExponentialBackOffForDeadline(context.Background())
+	if b.MaxElapsedTime != DefaultMaxElapsedTime || b.MaxInterval != DefaultMaxInterval {
+		t.Error("defaults are not used without a deadline")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	b = NewExponentialBackOffForDeadline(ctx)
+	if b.MaxInterval > 500*time.Millisecond {
+		t.Errorf("max interval too large: %v", b.MaxInterval)
+	}
+	if b.InitialInterval > 100*time.Millisecond {
+		t.Errorf("initial interval too large: %v", b.InitialInterval)
+	}
+	longest := time.Duration(float64(b.MaxInterval) * (1 + b.RandomizationFactor))
+	if b.MaxElapsedTime <= 0 || b.MaxElapsedTime+longest > 2*time.Second {
+		t.Errorf("schedule does not fit the deadline: max elapsed time %v", b.MaxElapsedTime)
+	}
+
+	// The schedule gives a sensible number of attempts before the deadline.
+	var n int
+	var total time.Duration
+	for total < b.MaxElapsedTime {
+		total += b.NextBackOff()
+		n++
+	}
+	if n < 5 {
+		t.Errorf("too few attempts: %d", n)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	b = NewExponentialBackOffForDeadline(ctx)
+	time.Sleep(time.Millisecond)
+	if b.NextBackOff() != Stop {
+		t.Error("expected stop after deadline")
+	}
+}
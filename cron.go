@@ -0,0 +1,180 @@
+package backoff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromTicker returns a BackOff waiting d between attempts, like a
+// time.Ticker created with time.NewTicker(d).
+func FromTicker(d time.Duration) BackOff {
+	return NewConstantBackOff(d)
+}
+
+// FromCron returns a BackOff whose intervals last until the next occurrence
+// of the cron schedule spec, so that code driven by a BackOff, such as a
+// Ticker, can also follow calendar schedules.
+//
+// spec has the five standard fields, minute, hour, day of month, month and
+// day of week (0 or 7 is Sunday), each of which is *, a number, a range
+// a-b, a list of them separated by commas, and optionally a step /n. The
+// shorthands @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are accepted too. Like in cron, a day matches if either the day of
+// month or the day of week matches when both are restricted.
+//
+// Times are in the local time zone. The BackOff stops if the schedule has no
+// occurrence in the next five years, e.g. for "0 0 30 2 *".
+func FromCron(spec string) (BackOff, error) {
+	s, err := parseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &cronBackOff{schedule: s, clock: SystemClock}, nil
+}
+
+type cronBackOff struct {
+	schedule *cronSchedule
+	clock    Clock
+}
+
+func (b *cronBackOff) NextBackOff() time.Duration {
+	now := b.clock.Now()
+	next := b.schedule.next(now)
+	if next.IsZero() {
+		return Stop
+	}
+	return next.Sub(now)
+}
+
+func (b *cronBackOff) Reset() {}
+
+var cronShorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule holds the values matched by each field as bit sets.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// anyDom and anyDow are set when the day field is *.
+	anyDom, anyDow bool
+}
+
+func parseCron(spec string) (*cronSchedule, error) {
+	if s, ok := cronShorthands[spec]; ok {
+		spec = s
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("backoff: cron spec %q must have 5 fields", spec)
+	}
+
+	s := &cronSchedule{}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("backoff: cron spec %q: %s", spec, err)
+		}
+		*b.field = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 << 0
+	}
+	s.anyDom = fields[2] == "*"
+	s.anyDow = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first occurrence after t, or the zero time if there is
+// none in the next five years.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestFromTicker(t *testing.T) {
+	assertSequence(t, FromTicker(time.Second), time.Second, time.Second)
+}
+
+func TestFromCron(t *testing.T) {
+	// Monday.
+	now := time.Date(2024, 1, 15, 10, 20, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 1, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * 3", time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		b, err := FromCron(c.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.spec, err)
+			continue
+		}
+		b.(*cronBackOff).clock = fixedClock(now)
+		if d := b.NextBackOff(); d != c.next.Sub(now) {
+			t.Errorf("%s: got %v, expected %v", c.spec, d, c.next.Sub(now))
+		}
+	}
+}
+
+func TestFromCronNoOccurrence(t *testing.T) {
+	b, err := FromCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := b.NextBackOff(); d != Stop {
+		t.Errorf("got %v, expected Stop", d)
+	}
+}
+
+func TestFromCronInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := FromCron(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
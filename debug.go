@@ -0,0 +1,54 @@
+package backoff
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DebugState describes the state of b and of the policies it wraps, from
+// the outermost decorator to the innermost policy, e.g.
+//
+//	WithMaxRetries{tries=3/3} -> ExponentialBackOff{interval=2s, attempt=3, ...}
+//
+// Policies implementing fmt.Stringer describe themselves; others are named
+// by their type.
+func DebugState(b BackOff) string {
+	var buf bytes.Buffer
+	for i, p := range UnwrapAll(b) {
+		if i > 0 {
+			buf.WriteString(" -> ")
+		}
+		if s, ok := p.(fmt.Stringer); ok {
+			buf.WriteString(s.String())
+		} else {
+			fmt.Fprintf(&buf, "%T", p)
+		}
+	}
+	return buf.String()
+}
+
+// GiveUpError is returned by a retry loop configured with WithDebugState
+// when it gives up. It records what the policy believed when it quit.
+type GiveUpError struct {
+	// Err is the error of the last attempt.
+	Err error
+	// State is the DebugState of the policy.
+	State string
+	// Reason tells why the retry loop gave up, e.g. "backoff stopped".
+	Reason string
+}
+
+func (e *GiveUpError) Error() string {
+	return fmt.Sprintf("%s (%s; %s)", e.Err, e.Reason, e.State)
+}
+
+// WithDebugState makes the retry loop return a *GiveUpError wrapping the
+// last error when it gives up, so that the state of the policy can be
+// inspected in logs and postmortems. Permanent errors are wrapped too.
+func WithDebugState() RetryOption {
+	return func(o *retryOptions) {
+		o.giveUps = append(o.giveUps, func(err error, _ []AttemptRecord) error {
+			return &GiveUpError{Err: err, State: DebugState(o.policy), Reason: o.reason}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package backoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestDebugState(t *testing.T) {
+	b := WithMaxRetries(WithContext(NewConstantBackOff(time.Second), context.Background()), 3)
+	b.NextBackOff()
+
+	expected := "WithMaxRetries{tries=1/3} -> WithContext{err=<nil>} -> ConstantBackOff{interval=1s}"
+	if s := DebugState(b); s != expected {
+		t.Errorf("invalid state: %s", s)
+	}
+}
+
+func TestDebugStateExponential(t *testing.T) {
+	b := NewExponentialBackOff()
+	b.NextBackOff()
+	b.NextBackOff()
+
+	s := DebugState(b)
+	for _, part := range []string{"interval=1.125s", "attempt=2", "initial=500ms", "multiplier=1.5"} {
+		if !strings.Contains(s, part) {
+			t.Errorf("%q does not contain %q", s, part)
+		}
+	}
+}
+
+func TestDebugStateCombined(t *testing.T) {
+	b := MaxOf(&ZeroBackOff{}, WithMinInterval(&StopBackOff{}, time.Second))
+	b.NextBackOff()
+
+	expected := "MaxOf{a=ZeroBackOff, b=WithMinInterval{min=1s} -> StopBackOff, aStopped=false, bStopped=true}"
+	if s := DebugState(b); s != expected {
+		t.Errorf("invalid state: %s", s)
+	}
+}
+
+func TestWithDebugState(t *testing.T) {
+	f := func() error { return errors.New("error") }
+	err := Retry(f, WithMaxRetries(&ZeroBackOff{}, 2), WithDebugState())
+
+	g, ok := err.(*GiveUpError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.Err.Error() != "error" {
+		t.Errorf("invalid error: %s", g.Err)
+	}
+	if g.Reason != reasonStopped {
+		t.Errorf("invalid reason: %s", g.Reason)
+	}
+	if g.State != "WithMaxRetries{tries=2/2} -> ZeroBackOff" {
+		t.Errorf("invalid state: %s", g.State)
+	}
+	if s := err.Error(); s != "error (backoff stopped; WithMaxRetries{tries=2/2} -> ZeroBackOff)" {
+		t.Errorf("invalid message: %s", s)
+	}
+
+	if err := Retry(func() error { return nil }, &ZeroBackOff{}, WithDebugState()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
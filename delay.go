@@ -0,0 +1,209 @@
+package backoff
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// UnsupportedPolicyError is returned by DelayForAttempt for a policy whose
+// delays cannot be computed without advancing it.
+type UnsupportedPolicyError struct {
+	Policy BackOff
+}
+
+func (e *UnsupportedPolicyError) Error() string {
+	return fmt.Sprintf("backoff: cannot compute the delay of %T without advancing it", e.Policy)
+}
+
+// DelayForAttempt returns the delay to wait before the given attempt of an
+// operation, attempt 1 being the first retry. It is the stateless counterpart
+// of NextBackOff for consumers that only know how many times an operation
+// was tried, such as message consumers implementing delayed redelivery from
+// a delivery count.
+//
+// DelayForAttempt returns 0 for attempts below 1, and never modifies b. It
+// supports the policies and decorators of this package, except WeightedOf
+// and FromSequence, and any BackOff that also implements Policy, in which
+// case a new instance is advanced. Other decorators implementing Wrapper are
+// assumed to return the intervals of the policy they wrap unchanged. For
+// other policies, it returns an *UnsupportedPolicyError.
+//
+// Since the time elapsed between attempts is unknown, it is taken to be the
+// sum of the previous delays: it is not taken into account for the
+// MaxElapsedTime of an ExponentialBackOff, and is the Elapsed time seen by
+// the conditions of StopWhen. The jitter of an ExponentialBackOff comes from
+// a private source; with SetSeed, the jitter is derived from the seed and
+// the attempt, so that the delay of an attempt is the same across calls and
+// processes.
+func DelayForAttempt(b BackOff, attempt int) (time.Duration, error) {
+	if attempt < 1 {
+		return 0, nil
+	}
+
+	switch p := b.(type) {
+	case *ZeroBackOff:
+		return 0, nil
+	case *StopBackOff:
+		return Stop, nil
+	case *ConstantBackOff:
+		return p.Interval, nil
+	case *ExponentialBackOff:
+		return p.delayForAttempt(attempt), nil
+	case *cronBackOff:
+		return p.NextBackOff(), nil
+	case *backOffTunable:
+		policy, _ := p.tunable.current()
+		return delayForPolicy(policy, attempt), nil
+	case *Combined:
+		return p.delayForAttempt(attempt)
+	case *backOffTries:
+		if p.maxTries > 0 && uint64(attempt) > p.maxTries {
+			return Stop, nil
+		}
+		return DelayForAttempt(p.delegate, attempt)
+	case *backOffContext:
+		if p.ctx.Err() != nil {
+			return Stop, nil
+		}
+		return DelayForAttempt(p.BackOff, attempt)
+	case *backOffStopWhen:
+		return p.delayForAttempt(attempt)
+	case *backOffSleepBudget:
+		slept, next, err := delaysUntil(p.delegate, attempt)
+		if err != nil || next == Stop || next > p.max-slept {
+			return Stop, err
+		}
+		return next, nil
+	}
+
+	if p, ok := b.(Policy); ok {
+		return delayForPolicy(p, attempt), nil
+	}
+	w, ok := b.(Wrapper)
+	if !ok {
+		return 0, &UnsupportedPolicyError{b}
+	}
+	next, err := DelayForAttempt(w.Unwrap(), attempt)
+	if err != nil || next == Stop {
+		return next, err
+	}
+
+	// Decorators adjusting the intervals of the wrapped policy.
+	switch p := b.(type) {
+	case *backOffScale:
+		return scaleDuration(next, p.factor), nil
+	case *backOffMin:
+		if next < p.min {
+			return p.min, nil
+		}
+	case *backOffLatency:
+		if floor := scaleDuration(p.stats.Latency(), p.factor); next < floor {
+			return floor, nil
+		}
+	case *backOffSpread:
+		if attempt == 1 {
+			return SaturatingAdd(next, p.offset), nil
+		}
+	case *backOffBlackout:
+		return p.postpone(next), nil
+	case *backOffHints:
+		return p.apply(next), nil
+	}
+	return next, nil
+}
+
+// delaysUntil returns the sum of the delays of b before attempt and the delay
+// of attempt, or Stop if b stops at or before attempt.
+func delaysUntil(b BackOff, attempt int) (slept, next time.Duration, err error) {
+	for i := 1; i <= attempt; i++ {
+		if next, err = DelayForAttempt(b, i); err != nil || next == Stop {
+			return slept, Stop, err
+		}
+		if i < attempt {
+			slept = SaturatingAdd(slept, next)
+		}
+	}
+	return slept, next, nil
+}
+
+// delayForPolicy advances a new instance of p attempt times.
+func delayForPolicy(p Policy, attempt int) time.Duration {
+	var next time.Duration
+	b := p.New()
+	b.Reset()
+	for i := 0; i < attempt; i++ {
+		if next = b.NextBackOff(); next == Stop {
+			break
+		}
+	}
+	return next
+}
+
+// delayForAttempt computes the delay of attempt on a copy of b.
+func (b *ExponentialBackOff) delayForAttempt(attempt int) time.Duration {
+	e := *b
+	e.currentInterval = e.InitialInterval
+	e.attempt = 0
+	for i := 1; i < attempt; i++ {
+		if e.NextInterval == nil && e.currentInterval == e.MaxInterval {
+			break
+		}
+		e.incrementCurrentInterval()
+	}
+	seed := time.Now().UnixNano()
+	if b.seeded {
+		seed = b.seed + int64(attempt)
+	}
+	return e.randomize(rand.New(rand.NewSource(seed)), e.currentInterval)
+}
+
+// delayForAttempt combines the delays of attempt like NextBackOff, a policy
+// having stopped if it stops at or before attempt.
+func (c *Combined) delayForAttempt(attempt int) (time.Duration, error) {
+	_, da, err := delaysUntil(c.a, attempt)
+	if err != nil {
+		return 0, err
+	}
+	_, db, err := delaysUntil(c.b, attempt)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case da == Stop && db == Stop:
+		return Stop, nil
+	case da == Stop || db == Stop:
+		if c.Rule == StopFirst {
+			return Stop, nil
+		}
+		if da == Stop {
+			return db, nil
+		}
+		return da, nil
+	}
+	if (da < db) != c.max {
+		return da, nil
+	}
+	return db, nil
+}
+
+// delayForAttempt evaluates the condition of b for every attempt up to
+// attempt, since b keeps stopping once the condition was true.
+func (b *backOffStopWhen) delayForAttempt(attempt int) (time.Duration, error) {
+	var slept time.Duration
+	for i := 1; i <= attempt; i++ {
+		next, err := DelayForAttempt(b.delegate, i)
+		if err != nil || next == Stop {
+			return Stop, err
+		}
+		if b.stop(State{Attempts: i, Elapsed: slept, Interval: next, Slept: slept}) {
+			return Stop, nil
+		}
+		if i == attempt {
+			return next, nil
+		}
+		slept = SaturatingAdd(slept, next)
+	}
+	return Stop, nil
+}
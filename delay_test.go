@@ -0,0 +1,129 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// delay returns DelayForAttempt(b, attempt), failing t on errors.
+func delay(t *testing.T, b BackOff, attempt int) time.Duration {
+	d, err := DelayForAttempt(b, attempt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return d
+}
+
+func TestDelayForAttempt(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.InitialInterval = time.Second
+	exp.RandomizationFactor = 0
+	exp.Multiplier = 2
+	exp.MaxInterval = 10 * time.Second
+	exp.Reset()
+
+	var expectedResults = []time.Duration{0, 1, 2, 4, 8, 10, 10}
+	for attempt, expected := range expectedResults {
+		assertEquals(t, expected*time.Second, delay(t, exp, attempt))
+	}
+	assertEquals(t, 10*time.Second, delay(t, exp, 1<<30))
+
+	// The policy itself is not advanced.
+	assertEquals(t, time.Second, exp.NextBackOff())
+
+	assertEquals(t, time.Minute, delay(t, NewConstantBackOff(time.Minute), 5))
+	assertEquals(t, Stop, delay(t, WithMaxRetries(&ZeroBackOff{}, 2), 3))
+	assertEquals(t, 0, delay(t, WithMaxRetries(&ZeroBackOff{}, 2), 2))
+}
+
+func TestDelayForAttemptJitter(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.InitialInterval = time.Second
+	exp.RandomizationFactor = 0.5
+	exp.Multiplier = 2
+	exp.Reset()
+
+	for i := 0; i < 100; i++ {
+		d := delay(t, exp, 3)
+		if d < 2*time.Second || d > 6*time.Second {
+			t.Fatalf("delay out of range: %v", d)
+		}
+	}
+}
+
+func TestDelayForAttemptSeeded(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.SetSeed(1)
+	exp.Reset()
+	expected := make([]time.Duration, 5)
+	for i := range expected {
+		expected[i] = exp.NextBackOff()
+	}
+
+	// Computing delays does not advance the source of the policy.
+	exp.Reset()
+	assertEquals(t, delay(t, exp, 3), delay(t, exp, 3))
+	for _, e := range expected {
+		assertEquals(t, e, exp.NextBackOff())
+	}
+}
+
+func TestDelayForAttemptDecorators(t *testing.T) {
+	second := NewConstantBackOff(time.Second)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	stats, _ := WithStats(second)
+	tunable := NewTunable(PolicyFunc(func() BackOff { return NewConstantBackOff(time.Minute) }))
+
+	cases := []struct {
+		name    string
+		b       BackOff
+		attempt int
+		delay   time.Duration
+	}{
+		{"WithContext", WithContext(second, context.Background()), 2, time.Second},
+		{"WithContext canceled", WithContext(second, canceled), 2, Stop},
+		{"WithTimeScale", WithTimeScale(second, 2), 1, 2 * time.Second},
+		{"WithMinInterval", WithMinInterval(&ZeroBackOff{}, time.Second), 3, time.Second},
+		{"WithStats", stats, 3, time.Second},
+		{"WithSpread first", WithSpread(second, 2, 1, time.Minute), 1, 31 * time.Second},
+		{"WithSpread later", WithSpread(second, 2, 1, time.Minute), 2, time.Second},
+		{"WithMaxCumulativeSleep", WithMaxCumulativeSleep(second, 2*time.Second), 2, time.Second},
+		{"WithMaxCumulativeSleep exceeded", WithMaxCumulativeSleep(second, 2*time.Second), 3, Stop},
+		{"StopWhen", StopWhen(second, MaxAttempts(3)), 2, time.Second},
+		{"StopWhen stopped", StopWhen(second, MaxAttempts(3)), 3, Stop},
+		{"StopWhen latched", StopWhen(policyBackOff{PolicyFunc(func() BackOff { return scripted(4, 5, 1) })}, BudgetExhausted(6)), 3, Stop},
+		{"MinOf", MinOf(second, WithMaxRetries(&ZeroBackOff{}, 1)), 1, 0},
+		{"MinOf stopped", MinOf(second, WithMaxRetries(&ZeroBackOff{}, 1)), 2, Stop},
+		{"Tunable", tunable.New(), 2, time.Minute},
+		{"WithHints", WithHints(second, NewMemoryHints(), "key"), 1, time.Second},
+	}
+	for _, c := range cases {
+		d, err := DelayForAttempt(c.b, c.attempt)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+		} else if d != c.delay {
+			t.Errorf("%s: got %v, want %v", c.name, d, c.delay)
+		}
+	}
+}
+
+func TestDelayForAttemptPolicy(t *testing.T) {
+	p := PolicyFunc(func() BackOff { return scripted(1, 2, 3) })
+	assertEquals(t, 2, delay(t, policyBackOff{p}, 2))
+	assertEquals(t, Stop, delay(t, policyBackOff{p}, 4))
+
+	if _, err := DelayForAttempt(scripted(1), 1); err == nil {
+		t.Error("expected an error")
+	} else if _, ok := err.(*UnsupportedPolicyError); !ok {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// policyBackOff is a BackOff that is also a Policy.
+type policyBackOff struct{ Policy }
+
+func (b policyBackOff) NextBackOff() time.Duration { panic("policy was advanced") }
+func (b policyBackOff) Reset()                     { panic("policy was reset") }
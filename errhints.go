@@ -0,0 +1,45 @@
+package backoff
+
+import "time"
+
+// retryAfter is implemented by errors that tell how long to wait before
+// retrying, such as errors carrying an HTTP Retry-After header.
+type retryAfter interface {
+	RetryAfter() time.Duration
+}
+
+// temporary is implemented by errors that tell whether they are temporary,
+// such as net.Error.
+type temporary interface {
+	Temporary() bool
+}
+
+// pushback returns the delay to wait after err: the delay hinted by err if
+// it has a RetryAfter method returning a longer delay than next, else next.
+func pushback(err error, next time.Duration) time.Duration {
+	if h, ok := err.(retryAfter); ok {
+		if d := h.RetryAfter(); d > next {
+			return d
+		}
+	}
+	return next
+}
+
+// WithoutTemporaryCheck retries errors that have a Temporary method
+// returning false, which are otherwise treated as permanent errors.
+//
+// Some errors report permanence too eagerly: a net.Error for a refused
+// connection is not temporary, although the server may come back.
+func WithoutTemporaryCheck() RetryOption {
+	return func(o *retryOptions) { o.ignoreTemporary = true }
+}
+
+// permanentByHint reports whether err is permanent according to its
+// Temporary method, unless WithoutTemporaryCheck is used.
+func (o *retryOptions) permanentByHint(err error) bool {
+	if o.ignoreTemporary {
+		return false
+	}
+	t, ok := err.(temporary)
+	return ok && !t.Temporary()
+}
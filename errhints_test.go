@@ -0,0 +1,72 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type hintedError struct {
+	after     time.Duration
+	temporary bool
+}
+
+func (e hintedError) Error() string             { return "hinted" }
+func (e hintedError) RetryAfter() time.Duration { return e.after }
+func (e hintedError) Temporary() bool           { return e.temporary }
+
+func TestRetryAfterHint(t *testing.T) {
+	var delays []time.Duration
+	attempts := 0
+	f := func() error {
+		attempts++
+		switch attempts {
+		case 1:
+			return hintedError{after: 20 * time.Millisecond, temporary: true}
+		case 2:
+			// Shorter hints do not shorten the delay.
+			return hintedError{after: time.Nanosecond, temporary: true}
+		}
+		return nil
+	}
+	notify := func(err error, d time.Duration) { delays = append(delays, d) }
+
+	if err := RetryNotify(f, NewConstantBackOff(time.Millisecond), notify); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(delays) != 2 || delays[0] != 20*time.Millisecond || delays[1] != time.Millisecond {
+		t.Errorf("invalid delays: %v", delays)
+	}
+}
+
+func TestTemporaryCheck(t *testing.T) {
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts == 1 {
+			return hintedError{temporary: true}
+		}
+		return hintedError{temporary: false}
+	}
+
+	err := Retry(f, &ZeroBackOff{})
+	if _, ok := err.(hintedError); !ok {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("invalid number of attempts: %d", attempts)
+	}
+
+	// With WithoutTemporaryCheck, Temporary is ignored.
+	attempts = 0
+	f = func() error {
+		if attempts++; attempts < 3 {
+			return hintedError{temporary: false}
+		}
+		return errors.New("done")
+	}
+	Retry(f, WithMaxRetries(&ZeroBackOff{}, 2), WithoutTemporaryCheck())
+	if attempts != 3 {
+		t.Errorf("non-temporary error was not retried: %d attempts", attempts)
+	}
+}
@@ -0,0 +1,113 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxHistory is the number of attempts kept in the history of a retry loop.
+const maxHistory = 100
+
+// AttemptRecord is the history of a single attempt of a retry loop.
+type AttemptRecord struct {
+	Attempt Attempt
+	// Start is the time the attempt started.
+	Start time.Time
+	// Duration is how long the attempt ran.
+	Duration time.Duration
+	// Err is the error returned by the attempt.
+	Err error
+	// Next is the delay waited after the attempt, 0 if it was the last one.
+	Next time.Duration
+}
+
+// Escalator handles the end of a retry loop that gave up. Each handler is
+// optional; they are called in order with the last error and the history of
+// the last 100 attempts.
+type Escalator struct {
+	// Fallback is called first. If it returns nil, the retry loop
+	// succeeds and the other handlers are not called. Otherwise the retry
+	// loop returns a *FallbackError, and the other handlers still receive
+	// the last error of the operation.
+	Fallback func(err error, history []AttemptRecord) error
+	// DeadLetter is called next, to keep the work that could not be done.
+	DeadLetter func(err error, history []AttemptRecord)
+	// Alert is called last.
+	Alert func(err error, history []AttemptRecord)
+}
+
+// FallbackError is returned by a retry loop whose Escalator fallback failed.
+type FallbackError struct {
+	// Err is the last error of the operation.
+	Err error
+	// Fallback is the error returned by the fallback.
+	Fallback error
+}
+
+func (e *FallbackError) Error() string {
+	return fmt.Sprintf("%s (fallback failed: %s)", e.Err, e.Fallback)
+}
+
+// WithEscalator calls the handlers of e when the retry loop gives up,
+// including when the operation returned a permanent error or the context
+// was canceled.
+func WithEscalator(e Escalator) RetryOption {
+	return func(o *retryOptions) {
+		o.recordHistory = true
+		o.giveUps = append(o.giveUps, e.handle)
+	}
+}
+
+func (e Escalator) handle(err error, history []AttemptRecord) error {
+	result := err
+	if e.Fallback != nil {
+		ferr := e.Fallback(err, history)
+		if ferr == nil {
+			return nil
+		}
+		result = &FallbackError{Err: err, Fallback: ferr}
+	}
+	if e.DeadLetter != nil {
+		e.DeadLetter(err, history)
+	}
+	if e.Alert != nil {
+		e.Alert(err, history)
+	}
+	return result
+}
+
+// giveUp runs the give-up handlers for err and returns the error the retry
+// loop returns.
+func (o *retryOptions) giveUp(err error) error {
+	for _, h := range o.giveUps {
+		if err = h(err, o.history); err == nil {
+			break
+		}
+	}
+	return err
+}
+
+func (o *retryOptions) recordStart(a Attempt) {
+	if !o.recordHistory {
+		return
+	}
+	if len(o.history) == maxHistory {
+		n := copy(o.history, o.history[1:])
+		o.history = o.history[:n]
+	}
+	o.history = append(o.history, AttemptRecord{Attempt: a, Start: time.Now()})
+}
+
+func (o *retryOptions) recordEnd(err error) {
+	if n := len(o.history); n > 0 {
+		r := &o.history[n-1]
+		r.Duration = time.Since(r.Start)
+		r.Err = err
+	}
+}
+
+func (o *retryOptions) recordNext(next time.Duration) {
+	if n := len(o.history); n > 0 {
+		o.history[n-1].Next = next
+	}
+}
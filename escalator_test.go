@@ -0,0 +1,83 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWithEscalator(t *testing.T) {
+	var calls []string
+	handler := func(name string) func(error, []AttemptRecord) {
+		return func(err error, history []AttemptRecord) {
+			calls = append(calls, fmt.Sprintf("%s %s %d", name, err, len(history)))
+		}
+	}
+	e := Escalator{
+		Fallback: func(err error, history []AttemptRecord) error {
+			calls = append(calls, "fallback")
+			return errors.New("fallback failed")
+		},
+		DeadLetter: handler("dead-letter"),
+		Alert:      handler("alert"),
+	}
+
+	f, _ := failWith("a", "b", "c", "d")
+	b := WithMaxRetries(NewConstantBackOff(time.Millisecond), 2)
+	err := Retry(f, b, WithEscalator(e))
+	ferr, ok := err.(*FallbackError)
+	if !ok || ferr.Err.Error() != "c" || ferr.Fallback.Error() != "fallback failed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := "[fallback dead-letter c 3 alert c 3]"
+	if fmt.Sprint(calls) != expected {
+		t.Errorf("got %v, expected %s", calls, expected)
+	}
+}
+
+func TestEscalatorFallback(t *testing.T) {
+	var history []AttemptRecord
+	e := Escalator{
+		Fallback: func(err error, h []AttemptRecord) error {
+			history = h
+			return nil
+		},
+		Alert: func(error, []AttemptRecord) { t.Error("alert after successful fallback") },
+	}
+
+	f, _ := failWith("a", "b", "c")
+	b := WithMaxRetries(NewConstantBackOff(time.Millisecond), 1)
+	if err := Retry(f, b, WithEscalator(e)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("invalid history: %+v", history)
+	}
+	for i, r := range history {
+		if r.Attempt.Number != i+1 || r.Start.IsZero() || r.Err == nil {
+			t.Errorf("invalid record: %+v", r)
+		}
+	}
+	if history[0].Err.Error() != "a" || history[0].Next != time.Millisecond || history[1].Next != 0 {
+		t.Errorf("invalid history: %+v", history)
+	}
+}
+
+func TestEscalatorHistoryLimit(t *testing.T) {
+	var history []AttemptRecord
+	e := Escalator{Alert: func(err error, h []AttemptRecord) { history = h }}
+
+	f := func() error { return errors.New("error") }
+	Retry(f, WithMaxRetries(&ZeroBackOff{}, 2*maxHistory), WithEscalator(e))
+	if len(history) != maxHistory {
+		t.Fatalf("invalid history length: %d", len(history))
+	}
+	if n := history[0].Attempt.Number; n != maxHistory+2 {
+		t.Errorf("invalid first attempt: %d", n)
+	}
+	if n := history[maxHistory-1].Attempt.Number; n != 2*maxHistory+1 {
+		t.Errorf("invalid last attempt: %d", n)
+	}
+}
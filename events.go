@@ -0,0 +1,91 @@
+package backoff
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+// Types of events sent by a retry loop.
+const (
+	// AttemptStarted is sent before each attempt.
+	AttemptStarted EventType = iota
+	// AttemptFailed is sent after each attempt that returned an error.
+	AttemptFailed
+	// Sleeping is sent before the retry loop sleeps for Event.Next.
+	Sleeping
+	// Succeeded is sent when the retry loop returns after a successful
+	// attempt.
+	Succeeded
+	// GaveUp is sent when the retry loop returns an error.
+	GaveUp
+	// HealthCheckFailed is sent when the check given with WithHealthCheck
+	// returns an error.
+	HealthCheckFailed
+)
+
+var eventTypeNames = []string{"AttemptStarted", "AttemptFailed", "Sleeping", "Succeeded", "GaveUp", "HealthCheckFailed"}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "EventType(" + strconv.Itoa(int(t)) + ")"
+	}
+	return eventTypeNames[t]
+}
+
+// Event is a structured event of a retry loop, sent on the channel given
+// with WithEvents.
+type Event struct {
+	Type EventType
+	Time time.Time
+	// Name is the name given with WithOperationName.
+	Name string
+	// Attempt is the current attempt. It is the last attempt for
+	// Succeeded and GaveUp events.
+	Attempt Attempt
+	// Err is the error of the attempt for AttemptFailed events, the error
+	// of the check for HealthCheckFailed events and the error returned by
+	// the retry loop for GaveUp events.
+	Err error
+	// Next is the delay before the next attempt for Sleeping events.
+	Next time.Duration
+	// Reason tells why the retry loop returned for Succeeded and GaveUp
+	// events, e.g. "backoff stopped".
+	Reason string
+}
+
+// WithEvents sends the events of the retry loop on ch, for consumption by
+// dashboards or tests. Sends block, so ch must be drained or buffered enough
+// not to slow down the retry loop. ch is not closed when the loop returns.
+func WithEvents(ch chan<- Event) RetryOption {
+	return func(o *retryOptions) {
+		send := func(e Event) {
+			e.Time = time.Now()
+			e.Name = o.name
+			ch <- e
+		}
+		o.before = append(o.before, func(a Attempt) {
+			send(Event{Type: AttemptStarted, Attempt: a})
+		})
+		o.after = append(o.after, func(a Attempt, err error) {
+			if err != nil {
+				send(Event{Type: AttemptFailed, Attempt: a, Err: err})
+			}
+		})
+		o.healthFailures = append(o.healthFailures, func(err error) {
+			send(Event{Type: HealthCheckFailed, Attempt: o.last, Err: err})
+		})
+		o.waits = append(o.waits, func(d time.Duration) {
+			send(Event{Type: Sleeping, Attempt: o.last, Next: d})
+		})
+		o.finishes = append(o.finishes, func(a Attempt, err error, reason string) {
+			if err == nil {
+				send(Event{Type: Succeeded, Attempt: a, Reason: reason})
+			} else {
+				send(Event{Type: GaveUp, Attempt: a, Err: err, Reason: reason})
+			}
+		})
+	}
+}
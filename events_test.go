@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithEvents(t *testing.T) {
+	events := make(chan Event, 100)
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("error")
+		}
+		return nil
+	}
+	if err := Retry(f, &ZeroBackOff{}, WithOperationName("op"), WithEvents(events)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	close(events)
+
+	var types []EventType
+	for e := range events {
+		types = append(types, e.Type)
+		if e.Name != "op" {
+			t.Errorf("%s: invalid name: %q", e.Type, e.Name)
+		}
+		switch e.Type {
+		case AttemptFailed:
+			if e.Err == nil || e.Attempt.Number != 1 {
+				t.Errorf("invalid event: %+v", e)
+			}
+		case Succeeded:
+			if e.Reason != reasonSucceeded || e.Attempt.Number != 2 {
+				t.Errorf("invalid event: %+v", e)
+			}
+		}
+	}
+	expected := []EventType{AttemptStarted, AttemptFailed, Sleeping, AttemptStarted, Succeeded}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("invalid events: %v", types)
+	}
+}
+
+func TestWithEventsGaveUp(t *testing.T) {
+	events := make(chan Event, 100)
+	Retry(func() error { return errors.New("error") }, &StopBackOff{}, WithEvents(events))
+	close(events)
+
+	var last Event
+	for e := range events {
+		last = e
+	}
+	if last.Type != GaveUp || last.Err == nil || last.Reason != reasonStopped {
+		t.Errorf("invalid event: %+v", last)
+	}
+	if s := EventType(42).String(); s != "EventType(42)" {
+		t.Errorf("invalid name: %s", s)
+	}
+}
@@ -26,13 +26,12 @@ func ExampleRetryContext() {
 	ctx := context.Background()
 
 	// An operation that may fail.
-	operation := func() error {
+	// Its context is canceled when the retry loop returns.
+	operation := func(ctx context.Context) error {
 		return nil // or an error
 	}
 
-	b := WithContext(NewExponentialBackOff(), ctx)
-
-	err := Retry(operation, b)
+	err := RetryContext(ctx, operation, NewExponentialBackOff())
 	if err != nil {
 		// Handle error.
 		return
@@ -1,6 +1,7 @@
 package backoff
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 )
@@ -60,10 +61,68 @@ type ExponentialBackOff struct {
 	// It never stops if MaxElapsedTime == 0.
 	MaxElapsedTime time.Duration
 	Clock          Clock
+	// Jitter is the distribution of the randomized interval
+	// within its range. The default is UniformJitter.
+	Jitter JitterDistribution
+	// NextInterval, if not nil, overrides the multiplicative growth of the
+	// retry interval. Multiplier is ignored when it is set.
+	NextInterval NextIntervalFunc
+	// Granularity, if positive, rounds the randomized intervals to the
+	// nearest multiple of it, for systems whose schedulers or logs have a
+	// coarse resolution. A positive interval is never rounded down to zero,
+	// nor rounded up above MaxInterval. Decorators such as WithMinInterval
+	// apply to the rounded intervals.
+	Granularity time.Duration
 
 	currentInterval time.Duration
+	attempt         int
 	startTime       time.Time
 	random          *rand.Rand
+	seed            int64
+	seeded          bool
+}
+
+// NextIntervalFunc computes the retry interval that follows prev. attempt is
+// the number of intervals computed so far since the last Reset, starting at 1.
+//
+// The returned interval is capped by MaxInterval and randomized with
+// RandomizationFactor just like the default exponential growth.
+type NextIntervalFunc func(prev time.Duration, attempt int) time.Duration
+
+// JitterDistribution is the distribution that the randomized interval of an
+// ExponentialBackOff is drawn from, within the range
+// [RetryInterval * (1 - RandomizationFactor), RetryInterval * (1 + RandomizationFactor)].
+type JitterDistribution int
+
+const (
+	// UniformJitter picks every value in the range with the same probability.
+	UniformJitter JitterDistribution = iota
+	// NormalJitter picks values from a normal distribution centered on the
+	// retry interval, truncated to the range.
+	NormalJitter
+	// ExponentialJitter picks values from an exponential distribution
+	// starting at the lower end of the range, truncated to the range.
+	// Most values are short, with a long tail that spreads clients apart.
+	ExponentialJitter
+)
+
+// sample returns a random value in [0, 1) drawn from the distribution.
+func (j JitterDistribution) sample(r *rand.Rand) float64 {
+	switch j {
+	case NormalJitter:
+		for {
+			if v := 0.5 + r.NormFloat64()/4; v >= 0 && v < 1 {
+				return v
+			}
+		}
+	case ExponentialJitter:
+		for {
+			if v := r.ExpFloat64() / 4; v < 1 {
+				return v
+			}
+		}
+	}
+	return r.Float64()
 }
 
 // Clock is an interface that returns current time for BackOff.
@@ -95,6 +154,39 @@ func NewExponentialBackOff() *ExponentialBackOff {
 	return b
 }
 
+// NewInfiniteExponentialBackOff creates an instance of ExponentialBackOff
+// using default values, except that it never stops: MaxElapsedTime is 0.
+func NewInfiniteExponentialBackOff() *ExponentialBackOff {
+	b := NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+	return b
+}
+
+// NewBoundedExponentialBackOff creates an instance of ExponentialBackOff
+// using default values, except that it stops after total time has elapsed.
+// MaxInterval is capped at total so that a single wait never exceeds it.
+func NewBoundedExponentialBackOff(total time.Duration) *ExponentialBackOff {
+	b := NewExponentialBackOff()
+	b.MaxElapsedTime = total
+	if b.MaxInterval > total {
+		b.MaxInterval = total
+	}
+	return b
+}
+
+// NewQuickBackOff creates an instance of ExponentialBackOff tuned for
+// interactive request paths, where the defaults, which suit background jobs,
+// would keep users waiting: 50ms initial interval, 1.5 multiplier, 1s
+// maximum interval and 5s maximum elapsed time.
+func NewQuickBackOff() *ExponentialBackOff {
+	b := NewExponentialBackOff()
+	b.InitialInterval = 50 * time.Millisecond
+	b.MaxInterval = time.Second
+	b.MaxElapsedTime = 5 * time.Second
+	b.Reset()
+	return b
+}
+
 type systemClock struct{}
 
 func (t systemClock) Now() time.Time {
@@ -107,7 +199,11 @@ var SystemClock = systemClock{}
 // Reset the interval back to the initial retry interval and restarts the timer.
 func (b *ExponentialBackOff) Reset() {
 	b.currentInterval = b.InitialInterval
+	b.attempt = 0
 	b.startTime = b.Clock.Now()
+	if b.seeded {
+		b.random = rand.New(rand.NewSource(b.seed))
+	}
 }
 
 // NextBackOff calculates the next backoff interval using the formula:
@@ -121,7 +217,31 @@ func (b *ExponentialBackOff) NextBackOff() time.Duration {
 	if b.random == nil {
 		b.random = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
-	return getRandomValueFromInterval(b.RandomizationFactor, b.random.Float64(), b.currentInterval)
+	return b.randomize(b.random, b.currentInterval)
+}
+
+// randomize returns the randomized interval for the retry interval using r.
+func (b *ExponentialBackOff) randomize(r *rand.Rand, interval time.Duration) time.Duration {
+	if !testModeEnabled() {
+		interval = getRandomValueFromInterval(b.RandomizationFactor, b.Jitter.sample(r), interval)
+	}
+	return b.round(interval)
+}
+
+// round rounds interval to the granularity of b.
+func (b *ExponentialBackOff) round(interval time.Duration) time.Duration {
+	g := b.Granularity
+	if g <= 0 || interval <= 0 {
+		return interval
+	}
+	rounded := SaturatingAdd(interval, g/2) / g * g
+	if rounded > b.MaxInterval && interval <= b.MaxInterval {
+		rounded -= g
+	}
+	if rounded <= 0 {
+		rounded = g
+	}
+	return rounded
 }
 
 // GetElapsedTime returns the elapsed time since an ExponentialBackOff instance
@@ -134,14 +254,33 @@ func (b *ExponentialBackOff) GetElapsedTime() time.Duration {
 	return b.Clock.Now().Sub(b.startTime)
 }
 
+// String describes the configuration and the current state of b.
+func (b *ExponentialBackOff) String() string {
+	var elapsed time.Duration
+	if !b.startTime.IsZero() {
+		elapsed = b.GetElapsedTime()
+	}
+	return fmt.Sprintf("ExponentialBackOff{interval=%v, attempt=%d, elapsed=%v, initial=%v, multiplier=%v, randomization=%v, max=%v, maxElapsed=%v}",
+		b.currentInterval, b.attempt, elapsed, b.InitialInterval, b.Multiplier, b.RandomizationFactor, b.MaxInterval, b.MaxElapsedTime)
+}
+
 // Increments the current interval by multiplying it with the multiplier.
 func (b *ExponentialBackOff) incrementCurrentInterval() {
-	// Check for overflow, if overflow is detected set the current interval to the max interval.
-	if float64(b.currentInterval) >= float64(b.MaxInterval)/b.Multiplier {
-		b.currentInterval = b.MaxInterval
-	} else {
-		b.currentInterval = time.Duration(float64(b.currentInterval) * b.Multiplier)
+	b.attempt++
+	if b.NextInterval != nil {
+		next := b.NextInterval(b.currentInterval, b.attempt)
+		if next < 0 || next > b.MaxInterval {
+			next = b.MaxInterval
+		}
+		b.currentInterval = next
+		return
+	}
+	// The product saturates instead of overflowing, then it is capped at the max interval.
+	next := SaturatingMul(b.currentInterval, b.Multiplier)
+	if next < 0 || next > b.MaxInterval {
+		next = b.MaxInterval
 	}
+	b.currentInterval = next
 }
 
 // Returns a random value from the following interval:
@@ -154,5 +293,7 @@ func getRandomValueFromInterval(randomizationFactor, random float64, currentInte
 	// Get a random value from the range [minInterval, maxInterval].
 	// The formula used below has a +1 because if the minInterval is 1 and the maxInterval is 3 then
 	// we want a 33% chance for selecting either 1, 2 or 3.
-	return time.Duration(minInterval + (random * (maxInterval - minInterval + 1)))
+	// The randomized interval may exceed the largest duration; it saturates
+	// rather than wrapping around to a negative value, which would be Stop.
+	return durationFromFloat(minInterval + (random * (maxInterval - minInterval + 1)))
 }
@@ -2,6 +2,7 @@ package backoff
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -52,6 +53,44 @@ func TestGetRandomizedInterval(t *testing.T) {
 	assertEquals(t, 3, getRandomValueFromInterval(0.5, 0.99, 2))
 }
 
+func TestJitterDistribution(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const n = 10000
+
+	mean := func(j JitterDistribution) float64 {
+		var sum float64
+		for i := 0; i < n; i++ {
+			v := j.sample(r)
+			if v < 0 || v >= 1 {
+				t.Fatalf("%d: sample out of range: %f", j, v)
+			}
+			sum += v
+		}
+		return sum / n
+	}
+
+	if m := mean(UniformJitter); math.Abs(m-0.5) > 0.02 {
+		t.Errorf("invalid uniform mean: %f", m)
+	}
+	if m := mean(NormalJitter); math.Abs(m-0.5) > 0.02 {
+		t.Errorf("invalid normal mean: %f", m)
+	}
+	if m := mean(ExponentialJitter); m > 0.3 {
+		t.Errorf("invalid exponential mean: %f", m)
+	}
+
+	exp := NewExponentialBackOff()
+	exp.Jitter = ExponentialJitter
+	exp.Reset()
+	for i := 0; i < 100; i++ {
+		d := exp.NextBackOff()
+		exp.currentInterval = exp.InitialInterval
+		if d < exp.InitialInterval/2 || d > exp.InitialInterval*3/2 {
+			t.Fatalf("interval out of range: %v", d)
+		}
+	}
+}
+
 type TestClock struct {
 	i     time.Duration
 	start time.Time
@@ -101,6 +140,156 @@ func TestBackOffOverflow(t *testing.T) {
 	assertEquals(t, testMaxInterval, exp.currentInterval)
 }
 
+func TestBackOffExtremeConfigurations(t *testing.T) {
+	configs := []struct {
+		multiplier    float64
+		randomization float64
+		max           time.Duration
+		granularity   time.Duration
+	}{
+		{10, 0.5, math.MaxInt64, 0},
+		{10, 0, math.MaxInt64, 0},
+		{10, 0.5, math.MaxInt64, time.Hour},
+		{1000, 0.9, math.MaxInt64 / 2, 0},
+		{math.MaxFloat64, 0.5, math.MaxInt64, 0},
+	}
+	for _, c := range configs {
+		exp := NewExponentialBackOff()
+		exp.InitialInterval = time.Millisecond
+		exp.Multiplier = c.multiplier
+		exp.RandomizationFactor = c.randomization
+		exp.MaxInterval = c.max
+		exp.Granularity = c.granularity
+		exp.MaxElapsedTime = 0
+		exp.Reset()
+
+		for i := 0; i < 5000; i++ {
+			if next := exp.NextBackOff(); next <= 0 {
+				t.Fatalf("multiplier %v, max %v: attempt %d returned %v", c.multiplier, c.max, i, next)
+			}
+			if exp.currentInterval <= 0 || exp.currentInterval > c.max {
+				t.Fatalf("multiplier %v, max %v: invalid current interval %v", c.multiplier, c.max, exp.currentInterval)
+			}
+		}
+	}
+}
+
+func TestNextIntervalFunc(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.InitialInterval = time.Second
+	exp.RandomizationFactor = 0
+	exp.MaxInterval = 20 * time.Second
+	// Quadratic growth: 1s, 4s, 9s, 16s, capped at 20s.
+	exp.NextInterval = func(prev time.Duration, attempt int) time.Duration {
+		n := time.Duration(attempt + 1)
+		return n * n * time.Second
+	}
+	exp.Reset()
+
+	var expectedResults = []time.Duration{1, 4, 9, 16, 20, 20}
+	for _, expected := range expectedResults {
+		assertEquals(t, expected*time.Second, exp.NextBackOff())
+	}
+
+	exp.Reset()
+	assertEquals(t, time.Second, exp.NextBackOff())
+	assertEquals(t, 4*time.Second, exp.NextBackOff())
+}
+
+func TestInfiniteExponentialBackOff(t *testing.T) {
+	exp := NewInfiniteExponentialBackOff()
+	exp.RandomizationFactor = 0
+	exp.Clock = &TestClock{i: 100 * time.Hour}
+	exp.Reset()
+
+	var expectedResults = []time.Duration{500000, 750000, 1125000, 1687500, 2531250}
+	for _, expected := range expectedResults {
+		assertEquals(t, expected*time.Microsecond, exp.NextBackOff())
+	}
+	for i := 0; i < 100; i++ {
+		if exp.NextBackOff() == Stop {
+			t.Fatal("infinite backoff stopped")
+		}
+	}
+	assertEquals(t, DefaultMaxInterval, exp.NextBackOff())
+}
+
+func TestBoundedExponentialBackOff(t *testing.T) {
+	exp := NewBoundedExponentialBackOff(2 * time.Second)
+	exp.RandomizationFactor = 0
+	exp.Clock = &TestClock{i: time.Second}
+	exp.Reset()
+
+	// The clock advances by one second on every call.
+	var expectedResults = []time.Duration{500, 750, Stop}
+	for _, expected := range expectedResults {
+		if expected != Stop {
+			expected *= time.Millisecond
+		}
+		assertEquals(t, expected, exp.NextBackOff())
+	}
+
+	exp = NewBoundedExponentialBackOff(time.Second)
+	assertEquals(t, time.Second, exp.MaxInterval)
+}
+
+func TestQuickBackOff(t *testing.T) {
+	exp := NewQuickBackOff()
+	if exp.RandomizationFactor != DefaultRandomizationFactor || exp.Multiplier != DefaultMultiplier {
+		t.Errorf("unexpected factors: %v, %v", exp.RandomizationFactor, exp.Multiplier)
+	}
+	exp.RandomizationFactor = 0
+	exp.Clock = &TestClock{}
+	exp.Reset()
+
+	// The clock advances by one second on every call.
+	var expectedResults = []time.Duration{50000, 75000, 112500, 168750, 253125, Stop}
+	for _, expected := range expectedResults {
+		if expected != Stop {
+			expected *= time.Microsecond
+		}
+		assertEquals(t, expected, exp.NextBackOff())
+	}
+
+	exp.MaxElapsedTime = 0
+	exp.Reset()
+	expectedResults = []time.Duration{50000000, 75000000, 112500000, 168750000, 253125000, 379687500, 569531250, 854296875, 1000000000, 1000000000}
+	for _, expected := range expectedResults {
+		assertEquals(t, expected, exp.NextBackOff())
+	}
+}
+
+func TestGranularity(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.Granularity = time.Second
+	exp.MaxInterval = 2500 * time.Millisecond
+
+	cases := []struct {
+		interval, expected time.Duration
+	}{
+		{0, 0},
+		{100 * time.Millisecond, time.Second},
+		{1400 * time.Millisecond, time.Second},
+		{1500 * time.Millisecond, 2 * time.Second},
+		{2400 * time.Millisecond, 2 * time.Second},
+		// Rounding up would exceed MaxInterval.
+		{2500 * time.Millisecond, 2 * time.Second},
+		// Jitter may exceed MaxInterval.
+		{3200 * time.Millisecond, 3 * time.Second},
+	}
+	for _, c := range cases {
+		assertEquals(t, c.expected, exp.round(c.interval))
+	}
+
+	exp.RandomizationFactor = 0.5
+	exp.Reset()
+	for i := 0; i < 10; i++ {
+		if d := exp.NextBackOff(); d%time.Second != 0 {
+			t.Errorf("interval is not rounded: %v", d)
+		}
+	}
+}
+
 func assertEquals(t *testing.T, expected, value time.Duration) {
 	if expected != value {
 		t.Errorf("got: %d, expected: %d", value, expected)
@@ -0,0 +1,76 @@
+package backoff
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+var (
+	expvarMu   sync.Mutex
+	expvarVars *expvarCounters
+)
+
+// PublishExpvar publishes counters of retry loops under the "backoff"
+// expvar variable, so they are served on /debug/vars.
+//
+// Counters are kept per operation name for every Retry or RetryNotify call
+// given a name with WithOperationName:
+//
+//	attempts   calls of the operation
+//	successes  retry loops that succeeded
+//	giveups    retry loops that returned an error
+//	inflight   retry loops currently running
+//
+// It is safe to call PublishExpvar more than once.
+func PublishExpvar() {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvarVars == nil {
+		expvarVars = &expvarCounters{ops: expvar.NewMap("backoff")}
+	}
+}
+
+func expvarObserver() Observer {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvarVars == nil {
+		return nil
+	}
+	return expvarVars
+}
+
+type expvarCounters struct {
+	mu  sync.Mutex
+	ops *expvar.Map
+}
+
+func (c *expvarCounters) op(name string) *expvar.Map {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, ok := c.ops.Get(name).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map).Init()
+	for _, key := range []string{"attempts", "successes", "giveups", "inflight"} {
+		m.Add(key, 0)
+	}
+	c.ops.Set(name, m)
+	return m
+}
+
+func (c *expvarCounters) Start(name string) { c.op(name).Add("inflight", 1) }
+
+func (c *expvarCounters) Attempt(name string) { c.op(name).Add("attempts", 1) }
+
+func (c *expvarCounters) Wait(name string, d time.Duration) {}
+
+func (c *expvarCounters) Finish(name string, err error) {
+	m := c.op(name)
+	m.Add("inflight", -1)
+	if err == nil {
+		m.Add("successes", 1)
+	} else {
+		m.Add("giveups", 1)
+	}
+}
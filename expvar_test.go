@@ -0,0 +1,54 @@
+package backoff
+
+import (
+	"errors"
+	"expvar"
+	"strconv"
+	"testing"
+)
+
+// expvarCounts returns the counters published for the operation name, which
+// are shared by all the runs of the test.
+func expvarCounts(name string) map[string]int64 {
+	counts := make(map[string]int64)
+	m, ok := expvar.Get("backoff").(*expvar.Map).Get(name).(*expvar.Map)
+	if !ok {
+		return counts
+	}
+	m.Do(func(kv expvar.KeyValue) {
+		counts[kv.Key], _ = strconv.ParseInt(kv.Value.String(), 10, 64)
+	})
+	return counts
+}
+
+func TestPublishExpvar(t *testing.T) {
+	PublishExpvar()
+	PublishExpvar()
+	before := expvarCounts("expvar-test")
+
+	var i = 0
+	f := func() error {
+		i++
+		if i == 3 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	b := &ZeroBackOff{}
+	if err := Retry(f, b, WithOperationName("expvar-test")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	Retry(func() error { return errors.New("error") }, WithMaxRetries(b, 1), WithOperationName("expvar-test"))
+
+	if _, ok := expvar.Get("backoff").(*expvar.Map).Get("expvar-test").(*expvar.Map); !ok {
+		t.Fatal("operation is not published")
+	}
+	after := expvarCounts("expvar-test")
+	expected := map[string]int64{"attempts": 5, "successes": 1, "giveups": 1, "inflight": 0}
+	for key, delta := range expected {
+		if d := after[key] - before[key]; d != delta {
+			t.Errorf("%s: got a change of %d, expected %d", key, d, delta)
+		}
+	}
+}
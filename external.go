@@ -0,0 +1,99 @@
+package backoff
+
+import (
+	"strconv"
+	"time"
+)
+
+// Attribute names used by RetryState.Encode and DecodeRetryState.
+const (
+	AttemptAttribute      = "backoff-attempt"
+	FirstAttemptAttribute = "backoff-first-attempt"
+)
+
+// RetryState is the state of an operation that is retried by an external
+// system performing the wait between attempts, for example a message queue
+// redelivering a message after a visibility timeout or a job scheduler.
+//
+// The state travels with the message, so no policy state has to be kept
+// in-process between attempts.
+type RetryState struct {
+	// Attempt is the number of failed attempts so far.
+	Attempt int
+	// First is the time of the first failed attempt.
+	First time.Time
+}
+
+// Scheduler is implemented by external systems that wait before the next
+// attempt of an operation.
+type Scheduler interface {
+	// Schedule arranges for the operation to be attempted again after
+	// delay, carrying state along.
+	Schedule(delay time.Duration, state RetryState) error
+}
+
+// Next records a failed attempt at time now and returns the new state
+// together with the delay before the next attempt, or Stop if the operation
+// should not be retried.
+//
+// The delay is computed with DelayForAttempt, whose error is returned for
+// policies it does not support. MaxElapsedTime of an *ExponentialBackOff is
+// measured from the first failed attempt.
+func (s RetryState) Next(b BackOff, now time.Time) (RetryState, time.Duration, error) {
+	if s.First.IsZero() {
+		s.First = now
+	}
+	s.Attempt++
+
+	var e *ExponentialBackOff
+	if As(b, &e) && e.MaxElapsedTime != 0 && now.Sub(s.First) > e.MaxElapsedTime {
+		return s, Stop, nil
+	}
+	next, err := DelayForAttempt(b, s.Attempt)
+	return s, next, err
+}
+
+// ScheduleRetry records a failed attempt of an operation in state and asks
+// s to schedule the next attempt. It returns false without calling s if b
+// says the operation should not be retried, or the error of RetryState.Next.
+func ScheduleRetry(s Scheduler, b BackOff, state RetryState) (bool, error) {
+	state, next, err := state.Next(b, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if next == Stop {
+		return false, nil
+	}
+	return true, s.Schedule(next, state)
+}
+
+// Encode returns the state as message attributes.
+func (s RetryState) Encode() map[string]string {
+	attrs := map[string]string{
+		AttemptAttribute: strconv.Itoa(s.Attempt),
+	}
+	if !s.First.IsZero() {
+		attrs[FirstAttemptAttribute] = s.First.UTC().Format(time.RFC3339Nano)
+	}
+	return attrs
+}
+
+// DecodeRetryState parses the state from message attributes created with
+// Encode. It returns the zero RetryState if attrs has no state, as is the
+// case for the first delivery of a message.
+func DecodeRetryState(attrs map[string]string) (RetryState, error) {
+	var s RetryState
+	var err error
+
+	if v, ok := attrs[AttemptAttribute]; ok {
+		if s.Attempt, err = strconv.Atoi(v); err != nil {
+			return RetryState{}, err
+		}
+	}
+	if v, ok := attrs[FirstAttemptAttribute]; ok {
+		if s.First, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return RetryState{}, err
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,87 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type testScheduler struct {
+	delays []time.Duration
+	state  RetryState
+}
+
+func (s *testScheduler) Schedule(delay time.Duration, state RetryState) error {
+	s.delays = append(s.delays, delay)
+	s.state = state
+	return nil
+}
+
+func TestScheduleRetry(t *testing.T) {
+	b := WithMaxRetries(NewConstantBackOff(time.Second), 2)
+	s := &testScheduler{}
+
+	var state RetryState
+	for i := 0; i < 2; i++ {
+		ok, err := ScheduleRetry(s, b, state)
+		if !ok || err != nil {
+			t.Fatalf("retry %d is not scheduled: %v", i, err)
+		}
+
+		// Pass the state through message attributes.
+		if state, err = DecodeRetryState(s.state.Encode()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ok, _ := ScheduleRetry(s, b, state); ok {
+		t.Error("retry is scheduled after policy stopped")
+	}
+	if len(s.delays) != 2 || s.delays[0] != time.Second || s.delays[1] != time.Second {
+		t.Errorf("invalid delays: %v", s.delays)
+	}
+	if state.Attempt != 2 || state.First.IsZero() {
+		t.Errorf("invalid state: %+v", state)
+	}
+}
+
+func TestRetryStateMaxElapsedTime(t *testing.T) {
+	exp := NewExponentialBackOff()
+	exp.MaxElapsedTime = time.Minute
+
+	start := time.Now()
+	state, next, err := RetryState{}.Next(exp, start)
+	if err != nil || next == Stop {
+		t.Errorf("unexpected stop or error: %v", err)
+	}
+	// MaxElapsedTime is found through decorators.
+	b := WithContext(exp, context.Background())
+	if _, next, _ = state.Next(b, start.Add(2*time.Minute)); next != Stop {
+		t.Errorf("expected stop, got %v", next)
+	}
+}
+
+func TestScheduleRetryUnsupported(t *testing.T) {
+	ok, err := ScheduleRetry(&testScheduler{}, WeightedOf(Weighted{Weight: 1, BackOff: &ZeroBackOff{}}), RetryState{})
+	if _, unsupported := err.(*UnsupportedPolicyError); ok || !unsupported {
+		t.Errorf("unexpected result: %v, %v", ok, err)
+	}
+}
+
+func TestDecodeRetryState(t *testing.T) {
+	s, err := DecodeRetryState(nil)
+	if err != nil || s.Attempt != 0 || !s.First.IsZero() {
+		t.Errorf("invalid state for missing attributes: %+v, %v", s, err)
+	}
+
+	if _, err = DecodeRetryState(map[string]string{AttemptAttribute: "x"}); err == nil {
+		t.Error("expected error for invalid attempt")
+	}
+
+	first := time.Date(2017, 12, 24, 1, 2, 3, 4, time.UTC)
+	s, err = DecodeRetryState(RetryState{Attempt: 3, First: first}.Encode())
+	if err != nil || s.Attempt != 3 || !s.First.Equal(first) {
+		t.Errorf("invalid decoded state: %+v, %v", s, err)
+	}
+}
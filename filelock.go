@@ -0,0 +1,133 @@
+package backoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// WithFileLock serializes the attempts of processes on one host that retry
+// the same resource, such as agents or cron jobs running in parallel.
+//
+// Each attempt holds an exclusive lock on the file at path, which is created
+// if it does not exist. After a failed attempt the time of the next attempt
+// is written to the file before the lock is released, and every process
+// waits for it before its own attempt, so that the processes collectively
+// follow the backoff of the last failure.
+//
+// Locking is only supported on Unix systems; elsewhere the retry loop
+// returns ErrFileLockUnsupported at once, as a permanent error, without
+// attempting the operation.
+func WithFileLock(path string) RetryOption {
+	return func(o *retryOptions) {
+		l := &fileLock{path: path}
+		o.locks = append(o.locks, l)
+		o.after = append(o.after, func(a Attempt, err error) {
+			if err == nil {
+				l.unlock()
+			}
+		})
+		o.waits = append(o.waits, l.release)
+		o.finishes = append(o.finishes, func(Attempt, error, string) { l.unlock() })
+	}
+}
+
+// ErrFileLockUnsupported is returned by retry loops using WithFileLock on
+// systems without file locks.
+var ErrFileLockUnsupported = errors.New("backoff: file locks are not supported on this system")
+
+type fileLock struct {
+	path string
+	f    *os.File
+}
+
+// acquire locks the file and waits until the time written in it, polling
+// until ctx is canceled.
+func (l *fileLock) acquire(ctx context.Context) error {
+	for {
+		f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0666)
+		if err != nil {
+			return err
+		}
+		ok, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if ok {
+			l.f = f
+			notBefore := l.notBefore()
+			if d := notBefore.Sub(time.Now()); d > 0 {
+				l.unlock()
+				if err = sleepContext(ctx, d); err != nil {
+					return err
+				}
+				continue
+			}
+			return nil
+		}
+		f.Close()
+		if err = sleepContext(ctx, 10*time.Millisecond); err != nil {
+			return err
+		}
+	}
+}
+
+// notBefore reads the time of the next attempt from the locked file.
+func (l *fileLock) notBefore() time.Time {
+	b, err := ioutil.ReadAll(l.f)
+	if err != nil {
+		return time.Time{}
+	}
+	nsec, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nsec)
+}
+
+// release writes the time of the next attempt and unlocks the file.
+func (l *fileLock) release(next time.Duration) {
+	if l.f == nil {
+		return
+	}
+	if err := l.f.Truncate(0); err == nil {
+		l.f.WriteAt([]byte(strconv.FormatInt(time.Now().Add(next).UnixNano(), 10)), 0)
+	}
+	l.unlock()
+}
+
+func (l *fileLock) unlock() {
+	if l.f == nil {
+		return
+	}
+	unlockFile(l.f)
+	l.f.Close()
+	l.f = nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lock acquires the file locks before an attempt.
+func (o *retryOptions) lock(ctx context.Context) error {
+	for _, l := range o.locks {
+		if err := l.acquire(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package backoff
+
+import "os"
+
+// tryLockFile fails permanently, so that the retry loop does not back off
+// until its policy stops.
+func tryLockFile(f *os.File) (bool, error) {
+	return false, Permanent(ErrFileLockUnsupported)
+}
+
+func unlockFile(f *os.File) error {
+	return ErrFileLockUnsupported
+}
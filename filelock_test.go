@@ -0,0 +1,86 @@
+package backoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithFileLock(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("file locks are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "backoff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lock")
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	f := func() error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return errors.New("error")
+	}
+
+	// Each lock is opened separately, like in different processes.
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Retry(f, WithMaxRetries(NewConstantBackOff(time.Millisecond), 2), WithFileLock(path))
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Errorf("attempts are not serialized: %d ran at once", maxRunning)
+	}
+}
+
+func TestWithFileLockNotBefore(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("file locks are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "backoff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lock")
+
+	// Another process failed and backs off for 50ms.
+	notBefore := time.Now().Add(50 * time.Millisecond)
+	if err := ioutil.WriteFile(path, []byte(strconv.FormatInt(notBefore.UnixNano(), 10)), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := Retry(func() error { return nil }, &ZeroBackOff{}, WithFileLock(path)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("shared backoff is not respected: %v", elapsed)
+	}
+}
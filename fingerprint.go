@@ -0,0 +1,69 @@
+package backoff
+
+// WithFingerprint sets the function telling whether consecutive attempts
+// failed with the same error: errors with equal fingerprints are the same.
+// The default fingerprint is the error message.
+//
+// The number of consecutive failures with the same error is reported in
+// Attempt.Repeats and Progress.Repeats.
+func WithFingerprint(fingerprint func(error) string) RetryOption {
+	return func(o *retryOptions) { o.fingerprint = fingerprint }
+}
+
+// StopAfterRepeats stops retrying once n consecutive attempts failed with
+// the same error, which suggests a dependency that is hard down.
+func StopAfterRepeats(n int) RetryOption {
+	return func(o *retryOptions) { o.maxRepeats = n }
+}
+
+// StopAfterChanges stops retrying once the error changed between n
+// consecutive failed attempts, which suggests a flapping dependency.
+func StopAfterChanges(n int) RetryOption {
+	return func(o *retryOptions) { o.maxChanges = n }
+}
+
+// Reasons for stopping retrying after comparing errors.
+const (
+	reasonRepeats = "same error repeated"
+	reasonChanges = "error keeps changing"
+)
+
+type errorStreak struct {
+	last    string
+	repeats int
+	changes int
+}
+
+// record updates the streak with the fingerprint of a failed attempt.
+func (s *errorStreak) record(fp string) {
+	switch {
+	case s.repeats == 0:
+		s.repeats = 1
+	case fp == s.last:
+		s.repeats++
+		s.changes = 0
+	default:
+		s.repeats = 1
+		s.changes++
+	}
+	s.last = fp
+}
+
+func (o *retryOptions) recordError(err error) {
+	fp := o.fingerprint
+	if fp == nil {
+		fp = error.Error
+	}
+	o.streak.record(fp(err))
+}
+
+// escalate returns the reason for stopping retrying early, if any.
+func (o *retryOptions) escalate() string {
+	switch {
+	case o.maxRepeats > 0 && o.streak.repeats >= o.maxRepeats:
+		return reasonRepeats
+	case o.maxChanges > 0 && o.streak.changes >= o.maxChanges:
+		return reasonChanges
+	}
+	return ""
+}
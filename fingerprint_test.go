@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func failWith(messages ...string) (Operation, *int) {
+	var i = 0
+	return func() error {
+		i++
+		if i > len(messages) {
+			return nil
+		}
+		return errors.New(messages[i-1])
+	}, &i
+}
+
+func TestAttemptRepeats(t *testing.T) {
+	var repeats []int
+	var progress []int
+	f, _ := failWith("a", "a", "b", "b", "b")
+
+	RetryAttempt(func(a Attempt) error {
+		repeats = append(repeats, a.Repeats)
+		return f()
+	}, &ZeroBackOff{}, WithProgress(func(p Progress) {
+		progress = append(progress, p.Repeats)
+	}))
+
+	if fmt.Sprint(repeats) != "[0 1 2 1 2 3]" {
+		t.Errorf("invalid attempt repeats: %v", repeats)
+	}
+	if fmt.Sprint(progress) != "[1 2 1 2 3]" {
+		t.Errorf("invalid progress repeats: %v", progress)
+	}
+}
+
+func TestStopAfterRepeats(t *testing.T) {
+	f, i := failWith("a", "b", "b", "b", "b")
+	err := Retry(f, &ZeroBackOff{}, StopAfterRepeats(3))
+	if err == nil || *i != 4 {
+		t.Errorf("unexpected error %v after %d attempts", err, *i)
+	}
+}
+
+func TestStopAfterChanges(t *testing.T) {
+	f, i := failWith("a", "a", "b", "c", "d", "e")
+	err := Retry(f, &ZeroBackOff{}, StopAfterChanges(3))
+	if err == nil || err.Error() != "d" || *i != 5 {
+		t.Errorf("unexpected error %v after %d attempts", err, *i)
+	}
+}
+
+func TestWithFingerprint(t *testing.T) {
+	// Errors are the same regardless of the request id.
+	prefix := func(err error) string {
+		return strings.SplitN(err.Error(), " ", 2)[0]
+	}
+	f, i := failWith("timeout 1", "timeout 2", "timeout 3", "refused 4")
+	err := Retry(f, &ZeroBackOff{}, WithFingerprint(prefix), StopAfterRepeats(3))
+	if err == nil || *i != 3 {
+		t.Errorf("unexpected error %v after %d attempts", err, *i)
+	}
+}
@@ -0,0 +1,93 @@
+package backoff
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// Gate is a switch that holds the retry loops and tickers registered with
+// it, for example to stop hammering a failing database during an incident
+// without restarting services. While the gate is closed, the next attempt
+// of every retry loop and the next tick of every ticker waits for the gate
+// to be reopened; running attempts are not interrupted.
+//
+// A Gate is safe for concurrent use. The zero value is not usable; create
+// gates with NewGate.
+type Gate struct {
+	mu   sync.Mutex
+	open chan struct{} // closed while the gate is open
+}
+
+// NewGate returns an open Gate.
+func NewGate() *Gate {
+	open := make(chan struct{})
+	close(open)
+	return &Gate{open: open}
+}
+
+// Close closes the gate, holding all registered retry loops and tickers
+// before their next attempt or tick.
+func (g *Gate) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.open:
+		g.open = make(chan struct{})
+	default:
+	}
+}
+
+// Open reopens the gate, releasing the retry loops and tickers it holds.
+func (g *Gate) Open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.open:
+	default:
+		close(g.open)
+	}
+}
+
+// IsOpen reports whether the gate is open.
+func (g *Gate) IsOpen() bool {
+	select {
+	case <-g.opened():
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the gate is open or ctx is canceled, in which case it
+// returns the error of ctx.
+func (g *Gate) Wait(ctx context.Context) error {
+	select {
+	case <-g.opened():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// opened returns a channel that is closed when the gate is open.
+func (g *Gate) opened() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.open
+}
+
+// WithGate registers the retry loop with g, holding each attempt while g is
+// closed. A retry loop held by g still stops when its context is canceled.
+func WithGate(g *Gate) RetryOption {
+	return func(o *retryOptions) { o.gates = append(o.gates, g) }
+}
+
+func (o *retryOptions) waitGates(ctx context.Context) error {
+	for _, g := range o.gates {
+		if err := g.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
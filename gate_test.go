@@ -0,0 +1,105 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestGate(t *testing.T) {
+	g := NewGate()
+	if !g.IsOpen() {
+		t.Fatal("new gate is closed")
+	}
+
+	g.Close()
+	g.Close()
+	if g.IsOpen() {
+		t.Fatal("gate is open after Close")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	g.Open()
+	g.Open()
+	if err := g.Wait(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWithGate(t *testing.T) {
+	g := NewGate()
+	attempts := make(chan int, 10)
+	f := func(a Attempt) error {
+		attempts <- a.Number
+		if a.Number == 1 {
+			g.Close()
+			return errors.New("error")
+		}
+		return nil
+	}
+
+	done := make(chan error)
+	go func() { done <- RetryAttempt(f, &ZeroBackOff{}, WithGate(g)) }()
+
+	<-attempts
+	select {
+	case n := <-attempts:
+		t.Fatalf("attempt %d was made while the gate is closed", n)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	g.Open()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if n := <-attempts; n != 2 {
+		t.Errorf("invalid attempt: %d", n)
+	}
+}
+
+func TestWithGateCanceled(t *testing.T) {
+	g := NewGate()
+	g.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := RetryContext(ctx, func(context.Context) error {
+		t.Error("attempt was made while the gate is closed")
+		return nil
+	}, &ZeroBackOff{}, WithGate(g))
+	if err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTickerWithGate(t *testing.T) {
+	g := NewGate()
+	ticker := NewTickerWithGate(NewConstantBackOff(time.Millisecond), g)
+	defer ticker.Stop()
+
+	<-ticker.C
+	g.Close()
+	// A tick may already be pending when the gate closes.
+	select {
+	case <-ticker.C:
+	case <-time.After(10 * time.Millisecond):
+	}
+	select {
+	case <-ticker.C:
+		t.Fatal("ticker ticks while the gate is closed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	g.Open()
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("ticker does not tick after the gate reopens")
+	}
+}
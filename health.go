@@ -0,0 +1,49 @@
+package backoff
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// WithHealthCheck switches the retry loop from blind retrying to probing
+// once the delay before a retry reaches threshold: check is called instead
+// of the operation, and the operation is attempted again only after check
+// succeeds. This saves expensive attempts during long outages.
+//
+// A failed check is handled like a failed attempt, so the loop keeps backing
+// off between checks, but the error of the last attempt remains the one
+// passed to notify and returned by the loop. The errors of the checks are
+// sent as HealthCheckFailed events instead. check is called with the context
+// of the BackOff; if it returns a *PermanentError, the loop returns its
+// wrapped error.
+func WithHealthCheck(threshold time.Duration, check func(ctx context.Context) error) RetryOption {
+	return func(o *retryOptions) {
+		o.healthThreshold = threshold
+		o.healthCheck = check
+	}
+}
+
+// errUnhealthy is returned by checkHealth when the check fails.
+var errUnhealthy = errors.New("backoff: health check failed")
+
+// checkHealth runs the health check if the last delay reached the threshold.
+// It is never run before the first attempt, so that a failed check always
+// has the error of an attempt to keep.
+func (o *retryOptions) checkHealth(ctx context.Context) error {
+	if o.healthCheck == nil || o.last.Number == 0 || o.next < o.healthThreshold {
+		return nil
+	}
+	err := o.healthCheck(ctx)
+	if err == nil {
+		return nil
+	}
+	for _, f := range o.healthFailures {
+		f(err)
+	}
+	if _, ok := err.(*PermanentError); ok {
+		return err
+	}
+	return errUnhealthy
+}
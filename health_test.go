@@ -0,0 +1,80 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	var events []string
+	var checks = 0
+	check := func(ctx context.Context) error {
+		checks++
+		events = append(events, fmt.Sprintf("check %d", checks))
+		if checks < 2 {
+			return errors.New("unhealthy")
+		}
+		return nil
+	}
+
+	var attempts = 0
+	f := func() error {
+		attempts++
+		events = append(events, fmt.Sprintf("attempt %d", attempts))
+		if attempts == 3 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	// Intervals reach the threshold of 10 after the first retry.
+	b := scripted(1, 10, 20, 30, 40)
+	if err := Retry(f, b, WithHealthCheck(10, check)); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	expected := "[attempt 1 attempt 2 check 1 check 2 attempt 3]"
+	if fmt.Sprint(events) != expected {
+		t.Errorf("got %v, expected %s", events, expected)
+	}
+}
+
+func TestWithHealthCheckKeepsError(t *testing.T) {
+	events := make(chan Event, 100)
+	opErr := errors.New("error")
+	unhealthy := errors.New("unhealthy")
+	var notified []error
+	notify := func(err error, _ time.Duration) { notified = append(notified, err) }
+
+	// Checks start before the first retry and never succeed.
+	check := func(ctx context.Context) error { return unhealthy }
+	err := RetryNotify(func() error { return opErr }, WithMaxRetries(&ZeroBackOff{}, 3), notify,
+		WithHealthCheck(0, check), WithEvents(events))
+	close(events)
+	if err != opErr {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(notified) != "[error error error]" {
+		t.Errorf("invalid notified errors: %v", notified)
+	}
+
+	var attempts, checks int
+	for e := range events {
+		switch e.Type {
+		case AttemptStarted:
+			attempts++
+		case HealthCheckFailed:
+			checks++
+			if e.Err != unhealthy || e.Attempt.Number != 1 {
+				t.Errorf("invalid event: %+v", e)
+			}
+		}
+	}
+	if attempts != 1 || checks != 3 {
+		t.Errorf("got %d attempts and %d checks", attempts, checks)
+	}
+}
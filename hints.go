@@ -0,0 +1,95 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// HintProvider stores advisory backoff hints per key, such as "this upstream
+// told us to back off until T". Implementations backed by shared storage
+// let the replicas of a service respect the hints received by any of them.
+//
+// Implementations must be safe for concurrent use.
+type HintProvider interface {
+	// Get returns the time until which callers should back off for key,
+	// or the zero time if there is no hint.
+	Get(key string) (time.Time, error)
+	// Set advises callers to back off for key until the given time.
+	Set(key string, until time.Time) error
+}
+
+/*
+WithHints creates a wrapper around another BackOff, which waits at least
+until the time hinted for key by p. Errors of p are ignored, so the wrapped
+policy is used alone when the hints are unavailable.
+
+Note: Implementation is not thread-safe.
+*/
+func WithHints(b BackOff, p HintProvider, key string) BackOff {
+	return &backOffHints{delegate: b, hints: p, key: key, now: time.Now}
+}
+
+type backOffHints struct {
+	delegate BackOff
+	hints    HintProvider
+	key      string
+	now      func() time.Time
+}
+
+func (b *backOffHints) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		return Stop
+	}
+	return b.apply(next)
+}
+
+// apply extends next until the time hinted for the key, if any.
+func (b *backOffHints) apply(next time.Duration) time.Duration {
+	until, err := b.hints.Get(b.key)
+	if err != nil || until.IsZero() {
+		return next
+	}
+	if d := until.Sub(b.now()); d > next {
+		return d
+	}
+	return next
+}
+
+func (b *backOffHints) Reset() {
+	b.delegate.Reset()
+}
+
+func (b *backOffHints) Unwrap() BackOff {
+	return b.delegate
+}
+
+// MemoryHints is a HintProvider keeping hints in memory, for a single
+// process. Expired hints are removed when they are read.
+type MemoryHints struct {
+	mu    sync.Mutex
+	hints map[string]time.Time
+}
+
+// NewMemoryHints returns an empty MemoryHints.
+func NewMemoryHints() *MemoryHints {
+	return &MemoryHints{hints: make(map[string]time.Time)}
+}
+
+func (m *MemoryHints) Get(key string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	until, ok := m.hints[key]
+	if ok && !until.After(time.Now()) {
+		delete(m.hints, key)
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (m *MemoryHints) Set(key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hints[key] = until
+	return nil
+}
@@ -0,0 +1,50 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingHints struct{}
+
+func (failingHints) Get(string) (time.Time, error) { return time.Time{}, errors.New("unavailable") }
+func (failingHints) Set(string, time.Time) error   { return errors.New("unavailable") }
+
+func TestWithHints(t *testing.T) {
+	start := time.Now()
+	hints := NewMemoryHints()
+	b := WithHints(NewConstantBackOff(time.Second), hints, "upstream").(*backOffHints)
+	b.now = func() time.Time { return start }
+
+	assertEquals(t, time.Second, b.NextBackOff())
+
+	hints.Set("upstream", start.Add(time.Minute))
+	assertEquals(t, time.Minute, b.NextBackOff())
+
+	hints.Set("upstream", start.Add(time.Millisecond))
+	assertEquals(t, time.Second, b.NextBackOff())
+
+	b = WithHints(NewConstantBackOff(time.Second), failingHints{}, "upstream").(*backOffHints)
+	assertEquals(t, time.Second, b.NextBackOff())
+	b = WithHints(&StopBackOff{}, hints, "upstream").(*backOffHints)
+	assertEquals(t, Stop, b.NextBackOff())
+}
+
+func TestMemoryHints(t *testing.T) {
+	hints := NewMemoryHints()
+	if until, _ := hints.Get("key"); !until.IsZero() {
+		t.Errorf("unexpected hint: %v", until)
+	}
+
+	until := time.Now().Add(time.Hour)
+	hints.Set("key", until)
+	if got, _ := hints.Get("key"); !got.Equal(until) {
+		t.Errorf("got %v, expected %v", got, until)
+	}
+
+	hints.Set("key", time.Now().Add(-time.Second))
+	if got, _ := hints.Get("key"); !got.IsZero() {
+		t.Errorf("expired hint is returned: %v", got)
+	}
+}
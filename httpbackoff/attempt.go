@@ -0,0 +1,35 @@
+package httpbackoff
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// AttemptHeader is the header in which Client sends the number of the
+// attempt of a request, starting at 1.
+const AttemptHeader = "X-Retry-Attempt"
+
+// RequestAttempt returns the number of the attempt of a request received by
+// a server, as sent by Client in AttemptHeader. It returns 1 if the header
+// is missing or invalid.
+func RequestAttempt(r *http.Request) int {
+	n, err := strconv.Atoi(r.Header.Get(AttemptHeader))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// ShedAbove returns a handler that rejects requests whose attempt is above
+// max with 503 Service Unavailable and passes the others to h. It helps an
+// overloaded server to shed the traffic of clients that have already
+// retried a lot, while the first attempts are still served.
+func ShedAbove(max int, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if RequestAttempt(r) > max {
+			http.Error(w, "too many attempts", http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
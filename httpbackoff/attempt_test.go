@@ -0,0 +1,43 @@
+package httpbackoff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRequestAttempt(t *testing.T) {
+	cases := map[string]int{"": 1, "x": 1, "0": 1, "1": 1, "3": 3}
+	for header, expected := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(AttemptHeader, header)
+		if n := RequestAttempt(r); n != expected {
+			t.Errorf("%q: got %d, expected %d", header, n, expected)
+		}
+	}
+}
+
+func TestClientSetsAttemptHeader(t *testing.T) {
+	var attempts []int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, RequestAttempt(r))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	// Attempts above 2 are shed before reaching the handler.
+	server := httptest.NewServer(ShedAbove(2, handler))
+	defer server.Close()
+
+	c := &Client{NewBackOff: zero}
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("invalid status: %d", resp.StatusCode)
+	}
+	if !reflect.DeepEqual(attempts, []int{1, 2}) {
+		t.Errorf("invalid attempts: %v", attempts)
+	}
+}
@@ -0,0 +1,227 @@
+// Package httpbackoff retries HTTP requests with backoff policies, taking
+// care of request and response bodies across attempts.
+//
+//	c := &httpbackoff.Client{}
+//	resp, err := c.Get("https://example.com/")
+package httpbackoff
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cenkalti/backoff"
+)
+
+// DefaultMaxBufferSize is the size of the largest request body buffered to
+// be sent again, unless configured otherwise.
+const DefaultMaxBufferSize = 1 << 20
+
+// maxDrainSize is the amount of a response body read before it is closed,
+// so that the connection can be reused by the next attempt.
+const maxDrainSize = 64 << 10
+
+// Client sends HTTP requests and retries them on failures. The zero value
+// is ready to use.
+//
+// Each request carries the number of the attempt in the AttemptHeader
+// header, starting at 1.
+//
+// The body of a failed response is drained and closed before the request
+// is sent again. When the client gives up after a failed response, the last
+// response is returned as is, with a nil error, like http.Client does for
+// any response; transport errors are returned with a nil response.
+type Client struct {
+	// HTTPClient sends the requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// NewBackOff returns the policy used for each request. It defaults to
+	// backoff.NewExponentialBackOff.
+	NewBackOff func() backoff.BackOff
+	// Retryable reports whether a request should be sent again after it
+	// returned resp and err. It defaults to DefaultRetryable.
+	Retryable func(req *http.Request, resp *http.Response, err error) bool
+	// MaxBufferSize is the size of the largest request body that is
+	// buffered to be sent again. Requests with larger bodies are sent only
+	// once, unless they have a GetBody function. It defaults to
+	// DefaultMaxBufferSize.
+	MaxBufferSize int64
+	// Options configure the retry loop of each request.
+	Options []backoff.RetryOption
+}
+
+// DefaultRetryable retries idempotent requests, and requests with an
+// Idempotency-Key header, after transport errors and responses with the
+// status codes 429, 502, 503 and 504.
+func DefaultRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// retryableResponse is returned by an attempt whose response is retried.
+type retryableResponse struct{ resp *http.Response }
+
+func (e *retryableResponse) Error() string {
+	return "httpbackoff: " + e.resp.Status
+}
+
+// Do sends req and retries it until it succeeds, the request is not
+// retryable or the policy stops. Retrying stops when the context of req is
+// canceled.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	getBody, err := c.rewind(req)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retryable := c.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	var b backoff.BackOff
+	if c.NewBackOff != nil {
+		b = c.NewBackOff()
+	} else {
+		b = backoff.NewExponentialBackOff()
+	}
+	if getBody == nil {
+		b = &backoff.StopBackOff{}
+	}
+
+	var last *http.Response
+	err = backoff.RetryContext(req.Context(), func(ctx context.Context) error {
+		if last != nil {
+			drain(last)
+			last = nil
+		}
+		r := req.Clone(ctx)
+		if a, ok := backoff.AttemptFromContext(ctx); ok {
+			r.Header.Set(AttemptHeader, strconv.Itoa(a.Number))
+		}
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				return backoff.Permanent(err)
+			}
+			r.Body = body
+		}
+		resp, err := client.Do(r)
+		if !retryable(req, resp, err) {
+			if err != nil {
+				return backoff.Permanent(err)
+			}
+			last = resp
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		last = resp
+		return &retryableResponse{resp}
+	}, b, c.Options...)
+
+	if last != nil {
+		return last, nil
+	}
+	return nil, err
+}
+
+// rewind returns a function returning a new copy of the body of req, or nil
+// if req cannot be sent again.
+func (c *Client) rewind(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return func() (io.ReadCloser, error) { return http.NoBody, nil }, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	max := c.MaxBufferSize
+	if max <= 0 {
+		max = DefaultMaxBufferSize
+	}
+	buf, err := ioutil.ReadAll(io.LimitReader(req.Body, max+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, err
+	}
+	if int64(len(buf)) > max {
+		// Too large to be buffered: send what was read and the rest once.
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+		return nil, nil
+	}
+	req.Body.Close()
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf)), nil
+	}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// drain reads the rest of the body of resp, up to a limit, and closes it.
+func drain(resp *http.Response) {
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainSize))
+	resp.Body.Close()
+}
+
+// Get issues a GET request to url.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Head issues a HEAD request to url.
+func (c *Client) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST request to url. Note that with DefaultRetryable, POST
+// requests are only retried if they have an Idempotency-Key header, which
+// requires building the request and calling Do.
+func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// PostForm issues a POST request to url with data URL-encoded as body.
+func (c *Client) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.Post(url, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
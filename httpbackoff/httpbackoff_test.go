@@ -0,0 +1,142 @@
+package httpbackoff
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cenkalti/backoff"
+)
+
+func zero() backoff.BackOff {
+	return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 3)
+}
+
+func TestClientRetries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("unavailable"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := &Client{NewBackOff: zero}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, ioutil.NopCloser(strings.NewReader("data")))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if b, _ := ioutil.ReadAll(resp.Body); string(b) != "ok" {
+		t.Errorf("invalid body: %q", b)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("invalid number of requests: %d", len(bodies))
+	}
+	for i, b := range bodies {
+		if b != "data" {
+			t.Errorf("%d: invalid request body: %q", i, b)
+		}
+	}
+}
+
+func TestClientGiveUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("bad gateway"))
+	}))
+	defer server.Close()
+
+	c := &Client{NewBackOff: zero}
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("invalid status: %d", resp.StatusCode)
+	}
+	if b, _ := ioutil.ReadAll(resp.Body); string(b) != "bad gateway" {
+		t.Errorf("body of last response is not readable: %q", b)
+	}
+	if requests != 4 {
+		t.Errorf("invalid number of requests: %d", requests)
+	}
+}
+
+func TestClientNotIdempotent(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := &Client{NewBackOff: zero}
+	resp, err := c.Post(server.URL, "text/plain", strings.NewReader("data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if requests != 1 {
+		t.Errorf("POST request was retried: %d requests", requests)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("data"))
+	req.Header.Set("Idempotency-Key", "key")
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if requests != 5 {
+		t.Errorf("POST request with idempotency key was not retried: %d requests", requests-1)
+	}
+}
+
+func TestClientLargeBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := &Client{NewBackOff: zero, MaxBufferSize: 4}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, ioutil.NopCloser(strings.NewReader("too large")))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 1 || bodies[0] != "too large" {
+		t.Errorf("invalid requests: %q", bodies)
+	}
+}
+
+func TestClientTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	c := &Client{NewBackOff: zero}
+	resp, err := c.Get(url)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected an error")
+	}
+	if resp != nil {
+		t.Error("unexpected response")
+	}
+}
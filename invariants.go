@@ -0,0 +1,78 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+WithInvariants creates a wrapper around another BackOff, which checks at
+runtime that the wrapped policy honors the invariants of the BackOff
+interface: it never returns a negative interval other than Stop, it keeps
+returning Stop until Reset once it returned Stop, and it does not stop
+immediately after Reset if it did not when first used. It is meant for
+developing custom policies and for fuzzing configurations in CI.
+
+violated is called with an error describing each violation. If violated is
+nil, WithInvariants panics instead. The intervals are returned unchanged.
+
+Note: Implementation is not thread-safe.
+*/
+func WithInvariants(b BackOff, violated func(err error)) BackOff {
+	if violated == nil {
+		violated = func(err error) { panic(err) }
+	}
+	return &backOffInvariants{delegate: b, violated: violated}
+}
+
+type backOffInvariants struct {
+	delegate BackOff
+	violated func(error)
+	// calls is the number of intervals since the last Reset and resets
+	// the number of Resets after the policy was used.
+	calls, resets int
+	stopped       bool
+	// startsStopped records whether the first interval before the first
+	// Reset was Stop.
+	startsStopped bool
+}
+
+func (b *backOffInvariants) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	b.calls++
+	switch {
+	case next < 0 && next != Stop:
+		b.violate("negative interval %v", next)
+	case b.stopped && next != Stop:
+		b.violate("interval %v after Stop", next)
+	}
+	if b.calls == 1 {
+		if b.resets == 0 {
+			b.startsStopped = next == Stop
+		} else if next == Stop && !b.startsStopped {
+			b.violate("Stop immediately after Reset")
+		}
+	}
+	if next == Stop {
+		b.stopped = true
+	}
+	return next
+}
+
+func (b *backOffInvariants) violate(format string, args ...interface{}) {
+	b.violated(fmt.Errorf("backoff: invariant violated by %T: "+format, append([]interface{}{b.delegate}, args...)...))
+}
+
+func (b *backOffInvariants) Reset() {
+	// The first Reset of a new policy starts its first schedule.
+	if b.calls > 0 {
+		b.resets++
+	}
+	b.calls = 0
+	b.stopped = false
+	b.delegate.Reset()
+}
+
+func (b *backOffInvariants) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,78 @@
+package backoff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// flappingBackOff returns Stop every other time.
+type flappingBackOff struct{ n int }
+
+func (b *flappingBackOff) NextBackOff() time.Duration {
+	if b.n++; b.n%2 == 0 {
+		return Stop
+	}
+	return time.Second
+}
+
+func (b *flappingBackOff) Reset() {}
+
+func TestWithInvariants(t *testing.T) {
+	var violations []error
+	violated := func(err error) { violations = append(violations, err) }
+
+	b := WithInvariants(WithMaxRetries(NewConstantBackOff(time.Second), 2), violated)
+	for i := 0; i < 3; i++ {
+		b.Reset()
+		assertSequence(t, b, time.Second, time.Second, Stop, Stop)
+	}
+	if len(violations) != 0 {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	b = WithInvariants(scripted(-2), violated)
+	b.NextBackOff()
+	if len(violations) != 1 || !strings.Contains(violations[0].Error(), "negative interval") {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	violations = nil
+	b = WithInvariants(&flappingBackOff{}, violated)
+	b.Reset()
+	assertSequence(t, b, time.Second, Stop, time.Second)
+	if len(violations) != 1 || !strings.Contains(violations[0].Error(), "after Stop") {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+
+	// stoppingBackOff ignores Reset.
+	violations = nil
+	b = WithInvariants(&stoppingBackOff{}, violated)
+	assertSequence(t, b, time.Second, Stop)
+	b.Reset()
+	b.NextBackOff()
+	if len(violations) != 1 || !strings.Contains(violations[0].Error(), "after Reset") {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+}
+
+// stoppingBackOff returns one interval and then stops forever.
+type stoppingBackOff struct{ n int }
+
+func (b *stoppingBackOff) NextBackOff() time.Duration {
+	if b.n++; b.n > 1 {
+		return Stop
+	}
+	return time.Second
+}
+
+func (b *stoppingBackOff) Reset() {}
+
+func TestWithInvariantsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	WithInvariants(scripted(-2), nil).NextBackOff()
+}
@@ -0,0 +1,85 @@
+package backoff
+
+import "time"
+
+// latencyWeight is the weight of a new sample in the moving average of the
+// latency of successful attempts.
+const latencyWeight = 0.2
+
+// WithLatency records the duration of successful attempts in s, which can
+// be created with WithStats, as an exponential moving average. See
+// Stats.Latency and WithLatencyFloor.
+func WithLatency(s *Stats) RetryOption {
+	return func(o *retryOptions) {
+		var start time.Time
+		o.before = append(o.before, func(Attempt) { start = time.Now() })
+		o.after = append(o.after, func(a Attempt, err error) {
+			if err == nil {
+				s.observeLatency(time.Since(start))
+			}
+		})
+	}
+}
+
+func (s *Stats) observeLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latency == 0 {
+		s.latency = d
+	} else {
+		s.latency += time.Duration(latencyWeight * float64(d-s.latency))
+	}
+}
+
+// Latency returns the smoothed duration of the successful attempts recorded
+// with WithLatency, or 0 if there are none. Unlike the intervals, it is kept
+// when the BackOff is Reset so that it reflects the typical latency of the
+// operation across retry loops.
+func (s *Stats) Latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latency
+}
+
+/*
+WithLatencyFloor creates a wrapper around another BackOff, which never
+returns an interval shorter than factor times the latency recorded in s,
+tying the schedule to the observed behavior of the operation instead of
+constants. For example, never retry sooner than twice the typical latency:
+
+	b, stats := backoff.WithStats(backoff.NewExponentialBackOff())
+	b = backoff.WithLatencyFloor(b, stats, 2)
+	err := backoff.Retry(op, b, backoff.WithLatency(stats))
+
+The intervals are unchanged as long as no latency was recorded.
+
+Note: Implementation is not thread-safe.
+*/
+func WithLatencyFloor(b BackOff, s *Stats, factor float64) BackOff {
+	return &backOffLatency{delegate: b, stats: s, factor: factor}
+}
+
+type backOffLatency struct {
+	delegate BackOff
+	stats    *Stats
+	factor   float64
+}
+
+func (b *backOffLatency) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		return Stop
+	}
+	if floor := scaleDuration(b.stats.Latency(), b.factor); next < floor {
+		return floor
+	}
+	return next
+}
+
+func (b *backOffLatency) Reset() {
+	b.delegate.Reset()
+}
+
+func (b *backOffLatency) Unwrap() BackOff {
+	return b.delegate
+}
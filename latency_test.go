@@ -0,0 +1,53 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsLatency(t *testing.T) {
+	_, s := WithStats(&ZeroBackOff{})
+	if l := s.Latency(); l != 0 {
+		t.Errorf("unexpected latency: %v", l)
+	}
+
+	s.observeLatency(100 * time.Millisecond)
+	s.observeLatency(200 * time.Millisecond)
+	if l := s.Latency(); l != 120*time.Millisecond {
+		t.Errorf("invalid latency: %v", l)
+	}
+
+	s.reset()
+	if l := s.Latency(); l != 120*time.Millisecond {
+		t.Errorf("latency was cleared by Reset: %v", l)
+	}
+}
+
+func TestWithLatency(t *testing.T) {
+	b, s := WithStats(&ZeroBackOff{})
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("error")
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	if err := Retry(f, b, WithLatency(s)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if l := s.Latency(); l < 10*time.Millisecond || l > time.Second {
+		t.Errorf("invalid latency: %v", l)
+	}
+}
+
+func TestWithLatencyFloor(t *testing.T) {
+	b, s := WithStats(scripted(10*time.Millisecond, time.Second, 10*time.Millisecond))
+	b = WithLatencyFloor(b, s, 2)
+
+	assertSequence(t, b, 10*time.Millisecond)
+	s.observeLatency(100 * time.Millisecond)
+	assertSequence(t, b, time.Second, 200*time.Millisecond, Stop)
+}
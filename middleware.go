@@ -0,0 +1,130 @@
+package backoff
+
+import (
+	"reflect"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// WrapFunc sets the function pointed to by fptr to a version of fn that is
+// retried with a policy returned by newBackOff for every call. fn must be a
+// function whose last result is an error, and fptr a pointer to a variable
+// of the same type; otherwise WrapFunc panics.
+//
+// Only the errors for which retryable returns true are retried; a nil
+// retryable retries all errors. If the first argument of fn is a
+// context.Context, retrying stops when it is canceled. The wrapped function
+// returns the results of the last call of fn with the error returned by
+// Retry.
+//
+// For example, retry the calls of a net/rpc client:
+//
+//	var call func(string, interface{}, interface{}) error
+//	backoff.WrapFunc(&call, client.Call, func() backoff.BackOff {
+//		return backoff.NewExponentialBackOff()
+//	}, nil)
+//	err := call("Arith.Multiply", args, &reply)
+func WrapFunc(fptr, fn interface{}, newBackOff func() BackOff, retryable Classifier, opts ...RetryOption) {
+	if fptr == nil {
+		panic("backoff: fptr cannot be nil")
+	}
+	ptr := reflect.ValueOf(fptr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		panic("backoff: fptr must be a non-nil pointer")
+	}
+	ptr.Elem().Set(wrapFunc(ptr.Elem().Type(), reflect.ValueOf(fn), newBackOff, retryable, opts))
+}
+
+// WrapClient wraps the methods of client with retries, like WrapFunc does
+// for a single function. dst must be a pointer to a struct whose fields are
+// functions named after the methods of client to wrap; each field is set to
+// the retrying version of the method with the same name. Unexported fields
+// are ignored. WrapClient panics if a method is missing or its type does not
+// match the field.
+//
+// For example:
+//
+//	var c struct {
+//		Get func(ctx context.Context, key string) ([]byte, error)
+//		Put func(ctx context.Context, key string, value []byte) error
+//	}
+//	backoff.WrapClient(&c, store, newBackOff, isTemporary)
+func WrapClient(dst, client interface{}, newBackOff func() BackOff, retryable Classifier, opts ...RetryOption) {
+	if dst == nil {
+		panic("backoff: dst cannot be nil")
+	}
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		panic("backoff: dst must be a non-nil pointer to a struct")
+	}
+	val := ptr.Elem()
+	typ := val.Type()
+	c := reflect.ValueOf(client)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Type.Kind() != reflect.Func {
+			panic("backoff: field " + field.Name + " is not a function")
+		}
+		m := c.MethodByName(field.Name)
+		if !m.IsValid() {
+			panic("backoff: client has no method " + field.Name)
+		}
+		val.Field(i).Set(wrapFunc(field.Type, m, newBackOff, retryable, opts))
+	}
+}
+
+func wrapFunc(typ reflect.Type, fn reflect.Value, newBackOff func() BackOff, retryable Classifier, opts []RetryOption) reflect.Value {
+	if fn.Kind() != reflect.Func {
+		panic("backoff: fn must be a function")
+	}
+	if fn.Type() != typ {
+		panic("backoff: cannot wrap " + fn.Type().String() + " as " + typ.String())
+	}
+	if typ.NumOut() == 0 || typ.Out(typ.NumOut()-1) != errorType {
+		panic("backoff: last result of " + typ.String() + " must be an error")
+	}
+	hasContext := typ.NumIn() > 0 && typ.In(0) == contextType
+
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		operation := func() error {
+			results = fn.Call(args)
+			err, _ := results[len(results)-1].Interface().(error)
+			if err != nil && retryable != nil && !retryable(err) {
+				return Permanent(err)
+			}
+			return err
+		}
+
+		b := newBackOff()
+		if hasContext {
+			if ctx, ok := args[0].Interface().(context.Context); ok && ctx != nil {
+				b = WithContext(b, ctx)
+			}
+		}
+		err := Retry(operation, b, opts...)
+		if results == nil {
+			// The loop returned before the first attempt, e.g. on a
+			// canceled context.
+			results = make([]reflect.Value, typ.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(typ.Out(i))
+			}
+		}
+		// The error may have been replaced by an Escalator.
+		if err == nil {
+			results[len(results)-1] = reflect.Zero(errorType)
+		} else {
+			results[len(results)-1] = reflect.ValueOf(&err).Elem()
+		}
+		return results
+	})
+}
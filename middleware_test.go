@@ -0,0 +1,110 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+type testClient struct {
+	calls int
+}
+
+func (c *testClient) Get(ctx context.Context, key string) (string, error) {
+	c.calls++
+	if c.calls < 3 {
+		return "", errors.New("temporary")
+	}
+	return "value of " + key, nil
+}
+
+func (c *testClient) Delete(key string) error {
+	c.calls++
+	return errors.New("not found")
+}
+
+func TestWrapFunc(t *testing.T) {
+	c := &testClient{}
+	var get func(context.Context, string) (string, error)
+	WrapFunc(&get, c.Get, func() BackOff { return &ZeroBackOff{} }, nil)
+
+	v, err := get(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v != "value of a" {
+		t.Errorf("invalid value: %q", v)
+	}
+	if c.calls != 3 {
+		t.Errorf("invalid number of calls: %d", c.calls)
+	}
+}
+
+func TestWrapFuncCanceled(t *testing.T) {
+	c := &testClient{}
+	var get func(context.Context, string) (string, error)
+	WrapFunc(&get, c.Get, func() BackOff { return &ZeroBackOff{} }, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := get(ctx, "a"); err == nil {
+		t.Error("expected an error")
+	}
+	if c.calls != 1 {
+		t.Errorf("invalid number of calls: %d", c.calls)
+	}
+}
+
+func TestWrapFuncNoAttempt(t *testing.T) {
+	c := &testClient{}
+	g := NewGate()
+	g.Close()
+	var get func(context.Context, string) (string, error)
+	WrapFunc(&get, c.Get, func() BackOff { return &ZeroBackOff{} }, nil, WithGate(g))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v, err := get(ctx, "a")
+	if err == nil {
+		t.Error("expected an error")
+	}
+	if v != "" {
+		t.Errorf("invalid value: %q", v)
+	}
+	if c.calls != 0 {
+		t.Errorf("invalid number of calls: %d", c.calls)
+	}
+}
+
+func TestWrapClient(t *testing.T) {
+	c := &testClient{}
+	var w struct {
+		Get    func(context.Context, string) (string, error)
+		Delete func(string) error
+	}
+	notFound := func(err error) bool { return err.Error() != "not found" }
+	WrapClient(&w, c, func() BackOff { return WithMaxRetries(&ZeroBackOff{}, 5) }, notFound)
+
+	if err := w.Delete("a"); err == nil || err.Error() != "not found" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if c.calls != 1 {
+		t.Errorf("non-retryable error was retried: %d calls", c.calls)
+	}
+	if v, err := w.Get(context.Background(), "b"); err != nil || v != "value of b" {
+		t.Errorf("unexpected result: %q, %v", v, err)
+	}
+}
+
+func TestWrapClientMissingMethod(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	var w struct {
+		Put func(string) error
+	}
+	WrapClient(&w, &testClient{}, func() BackOff { return &ZeroBackOff{} }, nil)
+}
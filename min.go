@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+WithMinInterval creates a wrapper around another BackOff, which never returns
+an interval shorter than min. It protects downstream services when an
+adaptive policy computes near-zero waits. Stop is returned unchanged.
+
+Note: Implementation is not thread-safe.
+*/
+func WithMinInterval(b BackOff, min time.Duration) BackOff {
+	return &backOffMin{delegate: b, min: min}
+}
+
+type backOffMin struct {
+	delegate BackOff
+	min      time.Duration
+}
+
+func (b *backOffMin) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next != Stop && next < b.min {
+		return b.min
+	}
+	return next
+}
+
+func (b *backOffMin) Reset() {
+	b.delegate.Reset()
+}
+
+func (b *backOffMin) String() string {
+	return fmt.Sprintf("WithMinInterval{min=%v}", b.min)
+}
+
+func (b *backOffMin) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,11 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithMinInterval(t *testing.T) {
+	b := WithMinInterval(scripted(0, 5*time.Millisecond, time.Second), 10*time.Millisecond)
+	assertSequence(t, b, 10*time.Millisecond, 10*time.Millisecond, time.Second, Stop)
+}
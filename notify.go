@@ -0,0 +1,43 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// ThrottleNotify returns a Notify that calls notify for the first burst
+// failures, and then at most once per every, dropping the other calls.
+// It keeps long outages from flooding logs with identical retry messages:
+//
+//	// Log the first 3 failures, then once per minute.
+//	notify = backoff.ThrottleNotify(notify, 3, time.Minute)
+//
+// The returned Notify is safe for concurrent use, so it can be shared by
+// several retry loops.
+func ThrottleNotify(notify Notify, burst int, every time.Duration) Notify {
+	t := &notifyThrottle{burst: burst, every: every}
+	return func(err error, d time.Duration) {
+		if t.allow(time.Now()) {
+			notify(err, d)
+		}
+	}
+}
+
+type notifyThrottle struct {
+	mu    sync.Mutex
+	burst int
+	every time.Duration
+	calls int
+	last  time.Time
+}
+
+func (t *notifyThrottle) allow(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls++
+	if t.calls <= t.burst || now.Sub(t.last) >= t.every {
+		t.last = now
+		return true
+	}
+	return false
+}
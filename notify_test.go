@@ -0,0 +1,36 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestThrottleNotify(t *testing.T) {
+	var notified int
+	notify := ThrottleNotify(func(error, time.Duration) { notified++ }, 3, time.Hour)
+
+	f := func() error { return errors.New("error") }
+	RetryNotify(f, WithMaxRetries(&ZeroBackOff{}, 10), notify)
+	if notified != 3 {
+		t.Errorf("invalid number of notifications: %d", notified)
+	}
+
+	th := &notifyThrottle{burst: 1, every: time.Minute}
+	start := time.Now()
+	expected := []struct {
+		at    time.Duration
+		allow bool
+	}{
+		{0, true},
+		{time.Second, false},
+		{time.Minute, true},
+		{90 * time.Second, false},
+		{2 * time.Minute, true},
+	}
+	for _, e := range expected {
+		if th.allow(start.Add(e.at)) != e.allow {
+			t.Errorf("at %v: expected allow=%v", e.at, e.allow)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// A RetryOption configures the behavior of Retry and RetryNotify.
+type RetryOption func(*retryOptions)
+
+type retryOptions struct {
+	name            string
+	observers       []Observer
+	idempotencyKey  func() string
+	before          []func(Attempt)
+	after           []func(Attempt, error)
+	refreshers      []*refresher
+	shutdown        <-chan struct{}
+	drainTimeout    time.Duration
+	waits           []func(time.Duration)
+	healthCheck     func(context.Context) error
+	healthThreshold time.Duration
+	healthFailures  []func(error)
+	finishes        []func(Attempt, error, string)
+	fingerprint     func(error) string
+	maxRepeats      int
+	maxChanges      int
+	recordHistory   bool
+	giveUps         []func(error, []AttemptRecord) error
+	attemptID       func() string
+	attemptContexts []func(context.Context, Attempt) (context.Context, func(error))
+	totalTimeout    time.Duration
+	stopBehavior    StopBehavior
+	attemptTimeout  func(time.Duration) time.Duration
+	locks           []*fileLock
+	policy          BackOff
+	gates           []*Gate
+	cleanups        []func(error) error
+	spinThreshold   time.Duration
+	ignoreTemporary bool
+	yieldEvery      int
+	yieldPause      time.Duration
+
+	// last is the last attempt made, lastErr the last error that will be
+	// retried and reason tells why the retry loop returned.
+	last     Attempt
+	lastErr  error
+	next     time.Duration
+	streak   errorStreak
+	history  []AttemptRecord
+	deadline time.Time
+	reason   string
+	retries  int
+}
+
+// Reasons for returning from a retry loop.
+const (
+	reasonSucceeded = "succeeded"
+	reasonPermanent = "permanent error"
+	reasonStopped   = "backoff stopped"
+	reasonShutdown  = "shutdown"
+)
+
+func newRetryOptions(opts []RetryOption) *retryOptions {
+	o := &retryOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.name != "" {
+		if v := expvarObserver(); v != nil {
+			o.observers = append(o.observers, v)
+		}
+	}
+	return o
+}
+
+// WithOperationName names the operation being retried.
+// Observers receive the name with every event.
+func WithOperationName(name string) RetryOption {
+	return func(o *retryOptions) { o.name = name }
+}
+
+// WithObserver adds an Observer that is notified of the progress of the
+// retry loop.
+func WithObserver(obs Observer) RetryOption {
+	return func(o *retryOptions) { o.observers = append(o.observers, obs) }
+}
+
+// WithIdempotencyKey generates a key with gen once per retry loop and passes
+// it to every attempt in Attempt.IdempotencyKey, so that an operation
+// calling an idempotency-aware API can send the same key on each retry.
+func WithIdempotencyKey(gen func() string) RetryOption {
+	return func(o *retryOptions) { o.idempotencyKey = gen }
+}
+
+// WithBeforeAttempt calls f before each attempt of the operation, for
+// example to refresh request headers or start a timer.
+func WithBeforeAttempt(f func(a Attempt)) RetryOption {
+	return func(o *retryOptions) { o.before = append(o.before, f) }
+}
+
+// WithAfterAttempt calls f after each attempt of the operation with the
+// error it returned, including nil on success. Unlike Notify, f is also
+// called for the last attempt.
+func WithAfterAttempt(f func(a Attempt, err error)) RetryOption {
+	return func(o *retryOptions) { o.after = append(o.after, f) }
+}
+
+// Observer is notified of the progress of retry loops, e.g. for collecting
+// metrics. name is the name given with WithOperationName.
+//
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	// Start is called when a retry loop begins.
+	Start(name string)
+	// Attempt is called before each call of the operation.
+	Attempt(name string)
+	// Wait is called with the delay before the operation is retried.
+	Wait(name string, d time.Duration)
+	// Finish is called when the retry loop returns err.
+	// A nil err means the operation has succeeded.
+	Finish(name string, err error)
+}
+
+func (o *retryOptions) start() {
+	for _, obs := range o.observers {
+		obs.Start(o.name)
+	}
+}
+
+func (o *retryOptions) beforeAttempt(a Attempt) {
+	o.last = a
+	o.recordStart(a)
+	for _, obs := range o.observers {
+		obs.Attempt(o.name)
+	}
+	for _, f := range o.before {
+		f(a)
+	}
+}
+
+func (o *retryOptions) afterAttempt(a Attempt, err error) {
+	o.recordEnd(err)
+	for _, f := range o.after {
+		f(a, err)
+	}
+}
+
+// prepare runs the steps needed before an attempt. The attempt is skipped
+// if it returns an error.
+func (o *retryOptions) prepare(ctx context.Context) error {
+	if err := o.waitGates(ctx); err != nil {
+		return err
+	}
+	if err := o.checkHealth(ctx); err != nil {
+		return err
+	}
+	if err := o.refresh(ctx); err != nil {
+		return err
+	}
+	return o.lock(ctx)
+}
+
+// failed is called with the errors that may be retried.
+func (o *retryOptions) failed(err error) {
+	o.lastErr = err
+	o.recordError(err)
+	o.markRefreshers(err)
+}
+
+func (o *retryOptions) wait(d time.Duration) {
+	o.next = d
+	o.recordNext(d)
+	for _, obs := range o.observers {
+		obs.Wait(o.name, d)
+	}
+	for _, f := range o.waits {
+		f(d)
+	}
+}
+
+func (o *retryOptions) finish(err error) {
+	for _, obs := range o.observers {
+		obs.Finish(o.name, err)
+	}
+	for _, f := range o.finishes {
+		f(o.last, err, o.reason)
+	}
+}
@@ -0,0 +1,104 @@
+// Package otelbackoff records metrics and traces of backoff retry loops with
+// OpenTelemetry.
+//
+//	obs, err := otelbackoff.NewObserver(otel.Meter("myapp"))
+//	if err != nil {
+//		// Handle error.
+//	}
+//	err = backoff.Retry(operation, backoff.NewExponentialBackOff(),
+//		backoff.WithOperationName("fetch"), backoff.WithObserver(obs))
+package otelbackoff
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys set on measurements and spans.
+const (
+	OperationKey = attribute.Key("backoff.operation")
+	AttemptKey   = attribute.Key("backoff.attempt")
+	AttemptIDKey = attribute.Key("backoff.attempt_id")
+)
+
+// Observer is a backoff.Observer that records the following instruments:
+//
+//	backoff.attempts  counter of operation calls
+//	backoff.delay     histogram of delays before retries, in seconds
+//	backoff.giveups   counter of retry loops that returned an error
+//
+// Every measurement has the operation name attribute.
+type Observer struct {
+	attempts metric.Int64Counter
+	delay    metric.Float64Histogram
+	giveUps  metric.Int64Counter
+}
+
+var _ backoff.Observer = (*Observer)(nil)
+
+// NewObserver creates the instruments of an Observer using meter.
+func NewObserver(meter metric.Meter) (*Observer, error) {
+	attempts, err := meter.Int64Counter("backoff.attempts",
+		metric.WithDescription("Number of operation calls made by retry loops."))
+	if err != nil {
+		return nil, err
+	}
+	delay, err := meter.Float64Histogram("backoff.delay",
+		metric.WithDescription("Delay before retrying a failed operation."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	giveUps, err := meter.Int64Counter("backoff.giveups",
+		metric.WithDescription("Number of retry loops that returned an error."))
+	if err != nil {
+		return nil, err
+	}
+	return &Observer{attempts: attempts, delay: delay, giveUps: giveUps}, nil
+}
+
+func attrs(name string) metric.MeasurementOption {
+	return metric.WithAttributes(OperationKey.String(name))
+}
+
+func (o *Observer) Start(name string) {}
+
+func (o *Observer) Attempt(name string) {
+	o.attempts.Add(context.Background(), 1, attrs(name))
+}
+
+func (o *Observer) Wait(name string, d time.Duration) {
+	o.delay.Record(context.Background(), d.Seconds(), attrs(name))
+}
+
+func (o *Observer) Finish(name string, err error) {
+	if err != nil {
+		o.giveUps.Add(context.Background(), 1, attrs(name))
+	}
+}
+
+// WithAttemptSpans starts a span with tracer for each attempt of a
+// backoff.ContextOperation. The span is named after the operation and
+// carries the attempt number and ID.
+func WithAttemptSpans(tracer trace.Tracer, operation string) backoff.RetryOption {
+	return backoff.WithAttemptContext(func(ctx context.Context, a backoff.Attempt) (context.Context, func(error)) {
+		ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(
+			OperationKey.String(operation),
+			AttemptKey.Int(a.Number),
+			AttemptIDKey.String(a.ID),
+		))
+		return ctx, func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	})
+}
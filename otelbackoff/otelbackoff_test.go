@@ -0,0 +1,106 @@
+package otelbackoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cenkalti/backoff"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestObserver(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	obs, err := NewObserver(provider.Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var i = 0
+	f := func() error {
+		i++
+		return errors.New("error")
+	}
+	b := backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2)
+	backoff.Retry(f, b, backoff.WithOperationName("op"), backoff.WithObserver(obs))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	sums := make(map[string]int64)
+	var delays uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					if v, _ := dp.Attributes.Value(OperationKey); v.AsString() != "op" {
+						t.Errorf("%s: invalid operation attribute: %v", m.Name, v)
+					}
+					sums[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					delays += dp.Count
+				}
+			}
+		}
+	}
+
+	if sums["backoff.attempts"] != 3 {
+		t.Errorf("invalid attempts: %d", sums["backoff.attempts"])
+	}
+	if sums["backoff.giveups"] != 1 {
+		t.Errorf("invalid giveups: %d", sums["backoff.giveups"])
+	}
+	if delays != 2 {
+		t.Errorf("invalid number of delays: %d", delays)
+	}
+}
+
+func TestWithAttemptSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	f := func(ctx context.Context) error {
+		if !trace.SpanFromContext(ctx).IsRecording() {
+			t.Error("attempt has no span")
+		}
+		a, _ := backoff.AttemptFromContext(ctx)
+		if a.Number == 2 {
+			return nil
+		}
+		return errors.New("error")
+	}
+	err := backoff.RetryContext(context.Background(), f, &backoff.ZeroBackOff{},
+		backoff.WithAttemptID(backoff.NewAttemptID), WithAttemptSpans(provider.Tracer("test"), "fetch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("invalid number of spans: %d", len(spans))
+	}
+	if spans[0].Name() != "fetch" || spans[0].Status().Code != codes.Error || spans[1].Status().Code == codes.Error {
+		t.Errorf("invalid spans: %v", spans)
+	}
+	for i, span := range spans {
+		attrs := attribute.NewSet(span.Attributes()...)
+		if v, _ := attrs.Value(AttemptKey); v.AsInt64() != int64(i+1) {
+			t.Errorf("invalid attempt attribute: %v", v)
+		}
+		if v, _ := attrs.Value(AttemptIDKey); len(v.AsString()) != 16 {
+			t.Errorf("invalid attempt id attribute: %v", v)
+		}
+	}
+}
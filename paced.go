@@ -0,0 +1,152 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// PacedTicker is like Ticker except that the countdown to the next tick
+// starts only when the consumer reports the outcome of the attempt made on
+// the previous tick by calling Done, so that long attempts, such as long
+// polls, do not eat into the interval.
+//
+// After a failed attempt, the next tick comes after the next interval of the
+// BackOff. After a successful attempt, the BackOff is Reset and the next tick
+// comes immediately.
+type PacedTicker struct {
+	C        <-chan time.Time
+	c        chan time.Time
+	b        BackOffContext
+	results  chan error
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	mu       sync.Mutex
+	err      error
+}
+
+// NewPacedTicker returns a new PacedTicker containing a channel that will
+// send the time of each tick. The first tick is sent immediately. The channel
+// is closed when Stop method is called or BackOff stops. It is not safe to
+// manipulate the provided backoff policy while the ticker is running.
+func NewPacedTicker(b BackOff) *PacedTicker {
+	c := make(chan time.Time)
+	t := &PacedTicker{
+		C:       c,
+		c:       c,
+		b:       ensureContext(b),
+		results: make(chan error),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	t.b.Reset()
+	go t.run()
+	return t
+}
+
+// Done reports the outcome of the attempt made on the last tick, starting
+// the countdown to the next tick. It returns immediately if the ticker has
+// stopped.
+func (t *PacedTicker) Done(err error) {
+	select {
+	case t.results <- err:
+	case <-t.done:
+	}
+}
+
+// Stop turns off the ticker. After Stop, no more ticks will be sent.
+func (t *PacedTicker) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
+
+// Start stops the ticker when ctx is canceled. Tickers run from their
+// creation; Start only ties them to the lifecycle of ctx.
+func (t *PacedTicker) Start(ctx context.Context) {
+	stopOnCancel(ctx, t.Stop, t.done)
+}
+
+// Close stops the ticker and waits until its goroutine has exited.
+func (t *PacedTicker) Close() error {
+	t.Stop()
+	<-t.done
+	return nil
+}
+
+// Healthy reports whether the ticker is still running.
+func (t *PacedTicker) Healthy() bool {
+	return t.Err() == nil
+}
+
+// Err returns nil while the ticker is running. Once the channel is closed it
+// reports why, like Ticker.Err.
+func (t *PacedTicker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *PacedTicker) setErr(err error) {
+	t.mu.Lock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.mu.Unlock()
+}
+
+func (t *PacedTicker) run() {
+	defer func() {
+		t.setErr(ErrTickerStopped)
+		close(t.c)
+		close(t.done)
+	}()
+
+	ctx := t.b.Context()
+	for {
+		select {
+		case t.c <- time.Now():
+		case <-t.stop:
+			return
+		case <-ctx.Done():
+			t.setErr(ctx.Err())
+			return
+		}
+
+		var err error
+		select {
+		case err = <-t.results:
+		case <-t.stop:
+			return
+		case <-ctx.Done():
+			t.setErr(ctx.Err())
+			return
+		}
+
+		if err == nil {
+			t.b.Reset()
+			continue
+		}
+		next := t.b.NextBackOff()
+		if next == Stop {
+			if err := ctx.Err(); err != nil {
+				t.setErr(err)
+			} else {
+				t.setErr(ErrBackOffStopped)
+			}
+			return
+		}
+
+		timer := time.NewTimer(sleepDuration(next))
+		select {
+		case <-timer.C:
+		case <-t.stop:
+			timer.Stop()
+			return
+		case <-ctx.Done():
+			timer.Stop()
+			t.setErr(ctx.Err())
+			return
+		}
+	}
+}
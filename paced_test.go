@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestPacedTicker(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	ticker := NewPacedTicker(NewConstantBackOff(interval))
+	defer ticker.Stop()
+
+	// A long attempt does not shorten the interval.
+	<-ticker.C
+	time.Sleep(2 * interval)
+	done := time.Now()
+	ticker.Done(errors.New("error"))
+	tick := <-ticker.C
+	if d := tick.Sub(done); d < interval {
+		t.Errorf("tick came too early: %v after the attempt", d)
+	}
+
+	// The next tick comes immediately after a success.
+	ticker.Done(nil)
+	select {
+	case <-ticker.C:
+	case <-time.After(interval / 2):
+		t.Error("tick did not come immediately after a success")
+	}
+}
+
+func TestPacedTickerStops(t *testing.T) {
+	ticker := NewPacedTicker(WithMaxRetries(&ZeroBackOff{}, 1))
+	ticks := 0
+	for range ticker.C {
+		ticks++
+		ticker.Done(errors.New("error"))
+	}
+	if ticks != 2 {
+		t.Errorf("invalid number of ticks: %d", ticks)
+	}
+	if err := ticker.Err(); err != ErrBackOffStopped {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Done does not block once the ticker has stopped.
+	ticker.Done(nil)
+}
+
+func TestPacedTickerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := NewPacedTicker(WithContext(&ZeroBackOff{}, ctx))
+	<-ticker.C
+	cancel()
+	for range ticker.C {
+	}
+	if err := ticker.Err(); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
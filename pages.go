@@ -0,0 +1,33 @@
+package backoff
+
+// A PageOperation fetches the page at cursor and returns the cursor of the
+// following page, or an empty string after the last page. The first page is
+// fetched with an empty cursor.
+type PageOperation func(cursor string) (next string, err error)
+
+// RetryPages calls operation for every page until it returns an empty
+// cursor. A failing page is retried with b, resuming from the cursor of the
+// last good page, and the policy is reset after each successful page so
+// that every page gets the full backoff schedule.
+//
+// Each page is a separate retry loop: opts apply to every page and
+// observers are notified of each page as an operation of its own.
+// RetryPages returns the error of the page that could not be fetched.
+func RetryPages(operation PageOperation, b BackOff, opts ...RetryOption) error {
+	var cursor string
+	for {
+		var next string
+		err := Retry(func() error {
+			var err error
+			next, err = operation(cursor)
+			return err
+		}, b, opts...)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
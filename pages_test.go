@@ -0,0 +1,53 @@
+package backoff
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRetryPages(t *testing.T) {
+	pages := map[string]string{"": "a", "a": "b", "b": ""}
+	var cursors []string
+	failures := 0
+	f := func(cursor string) (string, error) {
+		cursors = append(cursors, cursor)
+		// Every page fails twice before it can be fetched.
+		if failures < 2 {
+			failures++
+			return "", errors.New("error")
+		}
+		failures = 0
+		return pages[cursor], nil
+	}
+
+	// Two retries are only enough if the policy is reset after each page.
+	b := WithMaxRetries(&ZeroBackOff{}, 2)
+	if err := RetryPages(f, b); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []string{"", "", "", "a", "a", "a", "b", "b", "b"}
+	if !reflect.DeepEqual(cursors, expected) {
+		t.Errorf("invalid cursors: %q", cursors)
+	}
+}
+
+func TestRetryPagesError(t *testing.T) {
+	var cursors []string
+	f := func(cursor string) (string, error) {
+		cursors = append(cursors, cursor)
+		if cursor == "a" {
+			return "", errors.New("error")
+		}
+		return "a", nil
+	}
+
+	err := RetryPages(f, WithMaxRetries(&ZeroBackOff{}, 1))
+	if err == nil || err.Error() != "error" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := []string{"", "a", "a"}
+	if !reflect.DeepEqual(cursors, expected) {
+		t.Errorf("invalid cursors: %q", cursors)
+	}
+}
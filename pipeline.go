@@ -0,0 +1,161 @@
+package backoff
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// Stage is a step of a Pipeline: a function retried independently for
+// every item flowing through the stage.
+type Stage struct {
+	// Func transforms an item. It is retried with a policy returned by
+	// NewBackOff when it returns an error.
+	Func func(ctx context.Context, in interface{}) (out interface{}, err error)
+	// NewBackOff returns the policy for each item. It defaults to
+	// NewExponentialBackOff.
+	NewBackOff func() BackOff
+	// Retryable reports whether an error of Func is retried. All errors
+	// are retried if it is nil.
+	Retryable Classifier
+	// Concurrency is the number of items processed at once. It defaults
+	// to 1. Items may leave a stage with a concurrency above 1 in a
+	// different order than they came in.
+	Concurrency int
+	// Options configure the retry loop of each item.
+	Options []RetryOption
+}
+
+// Pipeline chains stages connected by unbuffered channels, so that a stage
+// that is slow or backing off holds back the stages before it.
+//
+// The first item that fails in any stage, after its retries, stops the
+// whole pipeline: the context of the stages is canceled and the output
+// channel is closed. Producers sending to the input channel should stop
+// on the same context to not block forever.
+type Pipeline struct {
+	out    <-chan interface{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	err    error
+}
+
+// NewPipeline starts passing the items received from in through stages in
+// order. The pipeline stops when in is closed and all items are processed,
+// when an item fails or when ctx is canceled.
+func NewPipeline(ctx context.Context, in <-chan interface{}, stages ...*Stage) *Pipeline {
+	p := &Pipeline{}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	for i, s := range stages {
+		in = p.run(s, in, i == len(stages)-1)
+	}
+	p.out = in
+	return p
+}
+
+// Out returns the channel receiving the items that went through all the
+// stages. It is closed when the pipeline stops.
+func (p *Pipeline) Out() <-chan interface{} {
+	return p.out
+}
+
+// Context returns the context of the stages, which is canceled when the
+// pipeline fails.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Err returns the error of the item that stopped the pipeline, or the error
+// of the context given to NewPipeline if it was canceled, once the output
+// channel is closed.
+func (p *Pipeline) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+	p.cancel()
+}
+
+// run starts the workers of a stage. The context of the pipeline is
+// released when the last stage is done.
+func (p *Pipeline) run(s *Stage, in <-chan interface{}, last bool) <-chan interface{} {
+	out := make(chan interface{})
+	n := s.Concurrency
+	if n < 1 {
+		n = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			p.work(s, in, out)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+		if last {
+			p.cancel()
+		}
+	}()
+	return out
+}
+
+func (p *Pipeline) work(s *Stage, in <-chan interface{}, out chan<- interface{}) {
+	for {
+		var v interface{}
+		var ok bool
+		select {
+		case v, ok = <-in:
+			if !ok {
+				return
+			}
+		case <-p.ctx.Done():
+			p.fail(p.ctx.Err())
+			return
+		}
+
+		res, err := s.call(p.ctx, v)
+		if err != nil {
+			p.fail(err)
+			return
+		}
+
+		select {
+		case out <- res:
+		case <-p.ctx.Done():
+			p.fail(p.ctx.Err())
+			return
+		}
+	}
+}
+
+// call retries Func for the item v.
+func (s *Stage) call(ctx context.Context, v interface{}) (interface{}, error) {
+	var b BackOff
+	if s.NewBackOff != nil {
+		b = s.NewBackOff()
+	} else {
+		b = NewExponentialBackOff()
+	}
+
+	var res interface{}
+	err := RetryContext(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = s.Func(ctx, v)
+		if err != nil && s.Retryable != nil && !s.Retryable(err) {
+			return Permanent(err)
+		}
+		return err
+	}, b, s.Options...)
+	return res, err
+}
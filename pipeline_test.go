@@ -0,0 +1,113 @@
+package backoff
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func zeroBackOff() BackOff { return &ZeroBackOff{} }
+
+func TestPipeline(t *testing.T) {
+	in := make(chan interface{})
+	go func() {
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	var mu sync.Mutex
+	failed := make(map[int]bool)
+	double := &Stage{
+		Func: func(ctx context.Context, v interface{}) (interface{}, error) {
+			i := v.(int)
+			mu.Lock()
+			defer mu.Unlock()
+			// Every item fails once.
+			if !failed[i] {
+				failed[i] = true
+				return nil, errors.New("error")
+			}
+			return 2 * i, nil
+		},
+		NewBackOff:  zeroBackOff,
+		Concurrency: 3,
+	}
+	increment := &Stage{
+		Func: func(ctx context.Context, v interface{}) (interface{}, error) {
+			return v.(int) + 1, nil
+		},
+	}
+
+	p := NewPipeline(context.Background(), in, double, increment)
+	var results []int
+	for v := range p.Out() {
+		results = append(results, v.(int))
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sort.Ints(results)
+	for i, r := range results {
+		if r != 2*(i+1)+1 {
+			t.Errorf("invalid results: %v", results)
+			break
+		}
+	}
+	if len(results) != 10 {
+		t.Errorf("invalid number of results: %d", len(results))
+	}
+}
+
+func TestPipelineFailure(t *testing.T) {
+	in := make(chan interface{})
+	p := NewPipeline(context.Background(), in, &Stage{
+		Func: func(ctx context.Context, v interface{}) (interface{}, error) {
+			if v.(int) == 3 {
+				return nil, errors.New("permanent")
+			}
+			return v, nil
+		},
+		NewBackOff: zeroBackOff,
+		Retryable:  func(err error) bool { return err.Error() != "permanent" },
+	})
+	go func() {
+		defer close(in)
+		for i := 1; ; i++ {
+			select {
+			case in <- i:
+			case <-p.Context().Done():
+				return
+			}
+		}
+	}()
+
+	n := 0
+	for range p.Out() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("invalid number of results: %d", n)
+	}
+	if err := p.Err(); err == nil || err.Error() != "permanent" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPipelineCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan interface{})
+	p := NewPipeline(ctx, in, &Stage{
+		Func: func(ctx context.Context, v interface{}) (interface{}, error) { return v, nil },
+	})
+	cancel()
+	for range p.Out() {
+	}
+	if err := p.Err(); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package backoff
+
+import (
+	"runtime"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// WithPrecisionSleep waits the delays shorter than threshold by spinning on
+// the clock instead of using a timer, whose granularity can exceed such
+// delays on some systems. It suits the retry of in-memory operations, such
+// as compare-and-swap loops, with delays of a few milliseconds at most;
+// spinning keeps a CPU busy, so threshold should stay small (e.g. 5ms).
+//
+// A spinning wait is interrupted like a sleep, by the cancellation of the
+// context or a shutdown.
+func WithPrecisionSleep(threshold time.Duration) RetryOption {
+	return func(o *retryOptions) { o.spinThreshold = threshold }
+}
+
+// spin waits for d or until one of the contexts is canceled or the loop is
+// shut down, in which case it returns the reason.
+func (o *retryOptions) spin(parent, ctx context.Context, d time.Duration) string {
+	deadline := time.Now().Add(d)
+	for time.Now().Before(deadline) {
+		select {
+		case <-parent.Done():
+			return parent.Err().Error()
+		case <-ctx.Done():
+			return o.doneReason(parent, ctx)
+		case <-o.shutdown:
+			return reasonShutdown
+		default:
+			runtime.Gosched()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestWithPrecisionSleep(t *testing.T) {
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts < 5 {
+			return errors.New("error")
+		}
+		return nil
+	}
+
+	start := time.Now()
+	err := Retry(f, NewConstantBackOff(time.Millisecond), WithPrecisionSleep(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 4*time.Millisecond {
+		t.Errorf("delays were not waited: %v", elapsed)
+	}
+}
+
+func TestWithPrecisionSleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := func(context.Context) error {
+		cancel()
+		return errors.New("error")
+	}
+
+	o := newRetryOptions([]RetryOption{WithPrecisionSleep(time.Hour)})
+	err := retryLoop(f, WithContext(NewConstantBackOff(time.Minute), ctx), nil, o)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if o.reason != context.Canceled.Error() {
+		t.Errorf("invalid reason: %s", o.reason)
+	}
+}
+
+func benchmarkSleep(b *testing.B, opts ...RetryOption) {
+	// Each iteration ideally takes one millisecond.
+	for i := 0; i < b.N; i++ {
+		attempts := 0
+		Retry(func() error {
+			if attempts++; attempts < 2 {
+				return errors.New("error")
+			}
+			return nil
+		}, NewConstantBackOff(time.Millisecond), opts...)
+	}
+}
+
+func BenchmarkTimerSleep(b *testing.B) {
+	benchmarkSleep(b)
+}
+
+func BenchmarkPrecisionSleep(b *testing.B) {
+	benchmarkSleep(b, WithPrecisionSleep(5*time.Millisecond))
+}
@@ -0,0 +1,29 @@
+package backoff
+
+import "golang.org/x/net/context"
+
+// A PreparedOperation acquires the resources it needs, such as a connection
+// or a slot in a pool, afresh for each attempt.
+type PreparedOperation interface {
+	// Acquire is called before each attempt. If it fails, the attempt is
+	// not made and the error is retried like an error of the attempt.
+	// Otherwise release is called when the attempt returns.
+	Acquire(ctx context.Context) (release func(), err error)
+	// Do makes an attempt with the acquired resources.
+	Do(ctx context.Context) error
+}
+
+// RetryPrepared is like RetryContext for a PreparedOperation. The resources
+// acquired for an attempt are always released when the attempt returns,
+// including when it is canceled or panics, so that no connection leaks
+// across retries.
+func RetryPrepared(ctx context.Context, operation PreparedOperation, b BackOff, opts ...RetryOption) error {
+	return RetryContext(ctx, func(ctx context.Context) error {
+		release, err := operation.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return operation.Do(ctx)
+	}, b, opts...)
+}
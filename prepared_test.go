@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+type testPrepared struct {
+	acquired, released, attempts int
+}
+
+func (p *testPrepared) Acquire(ctx context.Context) (func(), error) {
+	p.acquired++
+	if p.acquired == 1 {
+		return nil, errors.New("pool exhausted")
+	}
+	return func() { p.released++ }, nil
+}
+
+func (p *testPrepared) Do(ctx context.Context) error {
+	p.attempts++
+	if p.attempts < 3 {
+		return errors.New("error")
+	}
+	return nil
+}
+
+func TestRetryPrepared(t *testing.T) {
+	p := &testPrepared{}
+	if err := RetryPrepared(context.Background(), p, &ZeroBackOff{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if p.acquired != 4 || p.attempts != 3 {
+		t.Errorf("invalid number of acquisitions and attempts: %d, %d", p.acquired, p.attempts)
+	}
+	if p.released != p.attempts {
+		t.Errorf("resources are not released: %d released for %d attempts", p.released, p.attempts)
+	}
+}
@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+// Progress describes a failed attempt that is going to be retried.
+type Progress struct {
+	// Attempt is the attempt that failed.
+	Attempt Attempt
+	// Err is the error of the failed attempt.
+	Err error
+	// Next is the delay before the next attempt.
+	Next time.Duration
+	// Repeats is the number of consecutive attempts, up to this one, that
+	// failed with the same error. See WithFingerprint.
+	Repeats int
+}
+
+// String renders the progress for terminals, e.g.
+//
+//	attempt 4 failed: connection refused - retrying in 8s
+func (p Progress) String() string {
+	return fmt.Sprintf("attempt %d failed: %s - retrying in %s", p.Attempt.Number, p.Err, p.Next)
+}
+
+// WithProgress calls f before sleeping after each failed attempt, for
+// reporting progress in terminal user interfaces. Progress.String renders
+// a default message:
+//
+//	backoff.WithProgress(func(p backoff.Progress) {
+//		fmt.Fprintln(os.Stderr, p)
+//	})
+func WithProgress(f func(p Progress)) RetryOption {
+	return func(o *retryOptions) {
+		o.waits = append(o.waits, func(next time.Duration) {
+			f(Progress{Attempt: o.last, Err: o.lastErr, Next: next, Repeats: o.streak.repeats})
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithProgress(t *testing.T) {
+	var messages []string
+	f := func() error { return errors.New("connection refused") }
+	b := WithMaxRetries(scripted(4*time.Second, 8*time.Millisecond), 2)
+	SetTestMode(true)
+	SetTestTimeScale(0.001)
+	defer SetTestMode(false)
+	defer SetTestTimeScale(0)
+
+	Retry(f, b, WithProgress(func(p Progress) {
+		messages = append(messages, p.String())
+	}))
+
+	expected := []string{
+		"attempt 1 failed: connection refused - retrying in 4s",
+		"attempt 2 failed: connection refused - retrying in 8ms",
+	}
+	if len(messages) != len(expected) {
+		t.Fatalf("invalid messages: %q", messages)
+	}
+	for i, e := range expected {
+		if messages[i] != e {
+			t.Errorf("got %q, expected %q", messages[i], e)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package redishints implements a backoff.HintProvider storing hints in
+// Redis, so that the replicas of a service share the backoff hints received
+// by any of them.
+//
+// Unlike the backoff package, it depends on github.com/redis/go-redis/v9,
+// and its tests on github.com/alicebob/miniredis/v2.
+package redishints
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTimeout is the timeout of the Redis commands, unless configured
+// otherwise.
+const DefaultTimeout = 100 * time.Millisecond
+
+// Hints is a backoff.HintProvider backed by Redis. Each hint is stored
+// under Prefix followed by its key, and expires when the hinted time passes.
+//
+// Hints are read by backoff.WithHints while computing the next interval, so
+// every command is bounded by Timeout: when Redis is slow or unreachable,
+// the command fails and WithHints proceeds as if there were no hint. The
+// client only abandons the command itself if its ContextTimeoutEnabled
+// option is set; otherwise the command completes in the background.
+type Hints struct {
+	Client redis.Cmdable
+	// Prefix is prepended to the keys of hints.
+	Prefix string
+	// Timeout bounds each command. It defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+var _ backoff.HintProvider = (*Hints)(nil)
+
+// New returns Hints storing keys with the given prefix using client.
+func New(client redis.Cmdable, prefix string) *Hints {
+	return &Hints{Client: client, Prefix: prefix}
+}
+
+// Get returns the time stored for key, or the zero time if there is none.
+func (h *Hints) Get(key string) (time.Time, error) {
+	var v string
+	err := h.do(func(ctx context.Context) error {
+		var err error
+		v, err = h.Client.Get(ctx, h.Prefix+key).Result()
+		return err
+	})
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	nsec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, nsec), nil
+}
+
+// Set stores until for key. A time in the past removes the hint.
+func (h *Hints) Set(key string, until time.Time) error {
+	ttl := time.Until(until)
+	return h.do(func(ctx context.Context) error {
+		if ttl <= 0 {
+			return h.Client.Del(ctx, h.Prefix+key).Err()
+		}
+		return h.Client.Set(ctx, h.Prefix+key, strconv.FormatInt(until.UnixNano(), 10), ttl).Err()
+	})
+}
+
+// do runs cmd with a context bounded by the timeout of h, and returns when
+// cmd does or the timeout expires, whichever comes first.
+func (h *Hints) do(cmd func(ctx context.Context) error) error {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
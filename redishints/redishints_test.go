@@ -0,0 +1,87 @@
+package redishints
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cenkalti/backoff"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHints(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	// Two replicas sharing the same Redis.
+	a, b := New(client, "backoff:"), New(client, "backoff:")
+
+	if until, err := a.Get("upstream"); err != nil || !until.IsZero() {
+		t.Errorf("unexpected hint %v, %v", until, err)
+	}
+
+	until := time.Now().Add(time.Minute).Round(0)
+	if err := a.Set("upstream", until); err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.Get("upstream")
+	if err != nil || !got.Equal(until) {
+		t.Errorf("got %v, %v, expected %v", got, err, until)
+	}
+	if ttl := server.TTL("backoff:upstream"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("invalid ttl: %v", ttl)
+	}
+
+	p := backoff.WithHints(backoff.NewConstantBackOff(time.Second), b, "upstream")
+	if next := p.NextBackOff(); next < 59*time.Second {
+		t.Errorf("hint is not respected: %v", next)
+	}
+
+	if err = a.Set("upstream", time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if server.Exists("backoff:upstream") {
+		t.Error("past hint is not removed")
+	}
+}
+
+func TestHintsTimeout(t *testing.T) {
+	// A server that accepts connections but never replies.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: l.Addr().String(), MaxRetries: -1})
+	defer client.Close()
+	h := &Hints{Client: client, Prefix: "backoff:", Timeout: 20 * time.Millisecond}
+
+	start := time.Now()
+	if _, err := h.Get("upstream"); err == nil {
+		t.Error("expected an error")
+	}
+	if err := h.Set("upstream", time.Now().Add(time.Minute)); err == nil {
+		t.Error("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("commands were not bounded by the timeout: %v", elapsed)
+	}
+
+	// The policy proceeds without the hint.
+	p := backoff.WithHints(backoff.NewConstantBackOff(time.Second), h, "upstream")
+	if next := p.NextBackOff(); next != time.Second {
+		t.Errorf("unexpected interval: %v", next)
+	}
+}
@@ -0,0 +1,49 @@
+package backoff
+
+import "golang.org/x/net/context"
+
+// A Classifier reports whether an error belongs to some class of errors,
+// for example authentication failures.
+type Classifier func(error) bool
+
+type refresher struct {
+	refresh func(ctx context.Context) error
+	when    Classifier
+	pending bool
+}
+
+// WithRefresh runs refresh before the next attempt of the operation after
+// an attempt failed with an error matched by when. It is meant for steps
+// such as renewing an expired token or reconnecting.
+//
+// refresh is called with the context of the BackOff. If refresh fails, its
+// error is handled like an error of the operation and refresh is run again
+// before the following attempt.
+func WithRefresh(refresh func(ctx context.Context) error, when Classifier) RetryOption {
+	return func(o *retryOptions) {
+		o.refreshers = append(o.refreshers, &refresher{refresh: refresh, when: when})
+	}
+}
+
+// markRefreshers marks the refreshers matching err as pending.
+func (o *retryOptions) markRefreshers(err error) {
+	for _, r := range o.refreshers {
+		if r.when(err) {
+			r.pending = true
+		}
+	}
+}
+
+// refresh runs the pending refreshers.
+func (o *retryOptions) refresh(ctx context.Context) error {
+	for _, r := range o.refreshers {
+		if !r.pending {
+			continue
+		}
+		if err := r.refresh(ctx); err != nil {
+			return err
+		}
+		r.pending = false
+	}
+	return nil
+}
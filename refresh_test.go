@@ -0,0 +1,57 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+func TestWithRefresh(t *testing.T) {
+	var token, refreshes int
+	isUnauthorized := func(err error) bool { return err == errUnauthorized }
+	refresh := func(ctx context.Context) error {
+		refreshes++
+		if refreshes == 1 {
+			return errors.New("refresh failed")
+		}
+		token++
+		return nil
+	}
+
+	var attempts int
+	f := func() error {
+		attempts++
+		switch {
+		case attempts == 1:
+			return errors.New("other error")
+		case token == 0:
+			return errUnauthorized
+		}
+		return nil
+	}
+
+	err := Retry(f, &ZeroBackOff{}, WithRefresh(refresh, isUnauthorized))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	// The first refresh fails, so it is run again before the third attempt.
+	if attempts != 3 || refreshes != 2 {
+		t.Errorf("invalid number of attempts %d or refreshes %d", attempts, refreshes)
+	}
+}
+
+func TestWithRefreshPermanent(t *testing.T) {
+	refresh := func(ctx context.Context) error {
+		return Permanent(errors.New("cannot refresh"))
+	}
+	always := func(error) bool { return true }
+	f := func() error { return errUnauthorized }
+
+	err := Retry(f, &ZeroBackOff{}, WithRefresh(refresh, always))
+	if err == nil || err.Error() != "cannot refresh" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RefreshLoop calls refresh immediately, then every period, e.g. to reload
+// a cached configuration or feature flags, until ctx is canceled. It
+// returns the error of ctx.
+//
+// When refresh fails, it is retried with the intervals of b instead of the
+// period, which resumes after the next success. If b stops, refresh is
+// retried every period until it succeeds again. After each failure, stale
+// is called, unless it is nil, with the error and the age of the cached
+// value, that is the time since the last successful refresh, or since the
+// loop started if none succeeded.
+func RefreshLoop(ctx context.Context, refresh func(ctx context.Context) error, period time.Duration, b BackOff, stale func(age time.Duration, err error)) error {
+	last := time.Now()
+	failing := false
+	var wait time.Duration
+	for {
+		t := time.NewTimer(sleepDuration(wait))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		// The timer may have fired together with the cancellation.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := refresh(ctx)
+		if err == nil {
+			last = time.Now()
+			failing = false
+			wait = period
+			continue
+		}
+		if stale != nil {
+			stale(time.Since(last), err)
+		}
+		if !failing {
+			b.Reset()
+			failing = true
+		}
+		if wait = b.NextBackOff(); wait == Stop {
+			wait = period
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestRefreshLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Failures are retried without waiting for the period.
+	results := []error{nil, errors.New("1"), errors.New("2"), nil, errors.New("3")}
+	calls := 0
+	refresh := func(context.Context) error {
+		err := results[calls]
+		calls++
+		if calls == len(results) {
+			cancel()
+		}
+		return err
+	}
+
+	var ages []time.Duration
+	var errs []string
+	stale := func(age time.Duration, err error) {
+		ages = append(ages, age)
+		errs = append(errs, err.Error())
+	}
+
+	start := time.Now()
+	err := RefreshLoop(ctx, refresh, 20*time.Millisecond, &ZeroBackOff{}, stale)
+	if err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != len(results) {
+		t.Errorf("invalid number of calls: %d", calls)
+	}
+	// Two periods: after the first and the fourth call.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("the period was not waited: %v", elapsed)
+	}
+	if len(errs) != 3 || errs[0] != "1" || errs[1] != "2" || errs[2] != "3" {
+		t.Fatalf("invalid errors: %v", errs)
+	}
+	if ages[0] < 20*time.Millisecond || ages[1] < ages[0] {
+		t.Errorf("invalid ages: %v", ages)
+	}
+}
+
+func TestRefreshLoopStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	refresh := func(context.Context) error {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return errors.New("error")
+	}
+
+	// When the policy stops, failures are retried every period.
+	start := time.Now()
+	RefreshLoop(ctx, refresh, 10*time.Millisecond, &StopBackOff{}, nil)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("the period was not waited: %v", elapsed)
+	}
+}
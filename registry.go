@@ -0,0 +1,88 @@
+package backoff
+
+import (
+	"sort"
+	"sync"
+)
+
+// Policy creates new instances of a backoff policy. Policies are shared,
+// for example through Register, while the BackOff instances they create are
+// not thread-safe and belong to a single retry loop or ticker.
+type Policy interface {
+	New() BackOff
+}
+
+// PolicyFunc is a function implementing Policy.
+type PolicyFunc func() BackOff
+
+// New calls f.
+func (f PolicyFunc) New() BackOff { return f() }
+
+var (
+	policiesMu sync.RWMutex
+	policies   = make(map[string]Policy)
+)
+
+// Register makes a policy available by name, so that an application can
+// define its policies, e.g. "db", "s3" or "auth", in one place and refer to
+// them by name elsewhere:
+//
+//	backoff.Register("db", backoff.PolicyFunc(func() backoff.BackOff {
+//		return backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 5)
+//	}))
+//
+// Register panics if a policy is already registered with name or if p is
+// nil.
+func Register(name string, p Policy) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	if p == nil {
+		panic("backoff: Register policy is nil")
+	}
+	if _, dup := policies[name]; dup {
+		panic("backoff: Register called twice for policy " + name)
+	}
+	policies[name] = p
+}
+
+// Get returns the policy registered with name.
+func Get(name string) (Policy, bool) {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	p, ok := policies[name]
+	return p, ok
+}
+
+// Policies returns the sorted names of the registered policies.
+func Policies() []string {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Override replaces the policy registered with name, or registers it, until
+// restore is called. It is meant for tests, e.g. to retry without waiting:
+//
+//	defer backoff.Override("db", backoff.PolicyFunc(func() backoff.BackOff {
+//		return &backoff.ZeroBackOff{}
+//	}))()
+func Override(name string, p Policy) (restore func()) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	old, ok := policies[name]
+	policies[name] = p
+	return func() {
+		policiesMu.Lock()
+		defer policiesMu.Unlock()
+		if ok {
+			policies[name] = old
+		} else {
+			delete(policies, name)
+		}
+	}
+}
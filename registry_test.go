@@ -0,0 +1,56 @@
+package backoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	defer func() { policies = make(map[string]Policy) }()
+
+	Register("constant", PolicyFunc(func() BackOff { return NewConstantBackOff(time.Second) }))
+	Register("zero", PolicyFunc(func() BackOff { return &ZeroBackOff{} }))
+
+	p, ok := Get("constant")
+	if !ok {
+		t.Fatal("policy is not registered")
+	}
+	if a, b := p.New(), p.New(); a == b {
+		t.Error("policy does not return new instances")
+	}
+	if _, ok := Get("missing"); ok {
+		t.Error("unexpected policy")
+	}
+	if names := Policies(); !reflect.DeepEqual(names, []string{"constant", "zero"}) {
+		t.Errorf("invalid names: %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration does not panic")
+		}
+	}()
+	Register("zero", PolicyFunc(func() BackOff { return &ZeroBackOff{} }))
+}
+
+func TestOverride(t *testing.T) {
+	defer func() { policies = make(map[string]Policy) }()
+
+	Register("db", PolicyFunc(func() BackOff { return NewConstantBackOff(time.Second) }))
+	restore := Override("db", PolicyFunc(func() BackOff { return &ZeroBackOff{} }))
+	p, _ := Get("db")
+	if _, ok := p.New().(*ZeroBackOff); !ok {
+		t.Error("policy is not overridden")
+	}
+	restore()
+	p, _ = Get("db")
+	if _, ok := p.New().(*ConstantBackOff); !ok {
+		t.Error("policy is not restored")
+	}
+
+	Override("new", PolicyFunc(func() BackOff { return &ZeroBackOff{} }))()
+	if _, ok := Get("new"); ok {
+		t.Error("overridden policy is not removed")
+	}
+}
@@ -0,0 +1,150 @@
+package backoff
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// DefaultRenewalFraction is the fraction of the TTL of a lease after which
+// a RenewalScheduler renews it, unless configured otherwise.
+const DefaultRenewalFraction = 2.0 / 3
+
+// ErrInvalidTTL is returned by a RenewalScheduler when a renewal succeeds
+// with a TTL that is not positive.
+var ErrInvalidTTL = errors.New("backoff: renewal returned a non-positive TTL")
+
+// A RenewFunc renews a lease, such as an auth token or a certificate, and
+// returns its new TTL.
+type RenewFunc func(ctx context.Context) (ttl time.Duration, err error)
+
+// RenewalScheduler renews a lease periodically at a fraction of its TTL.
+// When a renewal fails, it is retried with a backoff policy until it
+// succeeds or the lease expires, after which the periodic schedule resumes
+// with the new TTL.
+type RenewalScheduler struct {
+	renew    RenewFunc
+	fraction float64
+	b        BackOff
+	opts     []RetryOption
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running bool
+	err     error
+}
+
+// NewRenewalScheduler returns a RenewalScheduler that calls renew after
+// fraction of the TTL of the lease has elapsed, and retries failed renewals
+// with b and opts. fraction defaults to DefaultRenewalFraction if it is not
+// in (0, 1].
+func NewRenewalScheduler(renew RenewFunc, fraction float64, b BackOff, opts ...RetryOption) *RenewalScheduler {
+	if fraction <= 0 || fraction > 1 {
+		fraction = DefaultRenewalFraction
+	}
+	return &RenewalScheduler{renew: renew, fraction: fraction, b: b, opts: opts}
+}
+
+// Run renews a lease with the given remaining TTL until ctx is canceled, in
+// which case it returns the error of ctx, or until a renewal could not
+// succeed before the lease expired or the backoff policy stopped, in which
+// case it returns the error of the last renewal. A renewal returning a TTL
+// that is not positive fails with ErrInvalidTTL and is not retried.
+func (s *RenewalScheduler) Run(ctx context.Context, ttl time.Duration) error {
+	expiry := time.Now().Add(ttl)
+	for {
+		t := time.NewTimer(sleepDuration(time.Duration(float64(ttl) * s.fraction)))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		var err error
+		if ttl, err = s.renewBefore(ctx, expiry); err != nil {
+			return err
+		}
+		expiry = time.Now().Add(ttl)
+	}
+}
+
+// Start runs the scheduler in a goroutine until ctx is canceled or Close is
+// called. The lease is renewed immediately, then periodically. Start has no
+// effect if the scheduler was already started.
+func (s *RenewalScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	s.running = true
+	go func() {
+		// There is no lease yet, so the first renewal has no deadline.
+		ttl, err := s.renewBefore(ctx, time.Time{})
+		if err == nil {
+			err = s.Run(ctx, ttl)
+		}
+		s.mu.Lock()
+		s.running = false
+		s.err = err
+		s.mu.Unlock()
+		close(s.done)
+	}()
+}
+
+// Close stops a scheduler started with Start and waits until it has
+// stopped. It returns the error of the last renewal if the scheduler had
+// stopped because of it.
+func (s *RenewalScheduler) Close() error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	cancel()
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == context.Canceled {
+		return nil
+	}
+	return s.err
+}
+
+// Healthy reports whether a scheduler started with Start is running, that
+// is the lease has not expired without being renewed.
+func (s *RenewalScheduler) Healthy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// renewBefore retries the renewal until it succeeds or expiry is reached,
+// if expiry is not zero.
+func (s *RenewalScheduler) renewBefore(ctx context.Context, expiry time.Time) (time.Duration, error) {
+	if !expiry.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, expiry)
+		defer cancel()
+	}
+
+	var ttl time.Duration
+	err := RetryContext(ctx, func(ctx context.Context) error {
+		var err error
+		ttl, err = s.renew(ctx)
+		if err == nil && ttl <= 0 {
+			// Renewing again at once would spin.
+			return Permanent(ErrInvalidTTL)
+		}
+		return err
+	}, s.b, s.opts...)
+	return ttl, err
+}
@@ -0,0 +1,82 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestRenewalScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls []time.Time
+	renew := func(context.Context) (time.Duration, error) {
+		calls = append(calls, time.Now())
+		switch len(calls) {
+		case 2, 3:
+			return 0, errors.New("error")
+		case 5:
+			cancel()
+		}
+		return 40 * time.Millisecond, nil
+	}
+
+	start := time.Now()
+	s := NewRenewalScheduler(renew, 0.5, NewConstantBackOff(time.Millisecond))
+	if err := s.Run(ctx, 40*time.Millisecond); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 5 {
+		t.Fatalf("invalid number of renewals: %d", len(calls))
+	}
+	if d := calls[0].Sub(start); d < 20*time.Millisecond {
+		t.Errorf("renewed too early: %v", d)
+	}
+	// Failed renewals are retried with the backoff policy.
+	if d := calls[2].Sub(calls[1]); d > 15*time.Millisecond {
+		t.Errorf("failed renewal was not retried promptly: %v", d)
+	}
+}
+
+func TestRenewalSchedulerExpired(t *testing.T) {
+	attempts := 0
+	renew := func(context.Context) (time.Duration, error) {
+		attempts++
+		return 0, errors.New("error")
+	}
+
+	s := NewRenewalScheduler(renew, 0.5, NewConstantBackOff(time.Millisecond))
+	done := make(chan error)
+	go func() { done <- s.Run(context.Background(), 20*time.Millisecond) }()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "error" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("renewal is retried after the lease expired")
+	}
+	if attempts < 2 {
+		t.Errorf("failed renewal was not retried: %d attempts", attempts)
+	}
+}
+
+func TestRenewalSchedulerInvalidTTL(t *testing.T) {
+	renewals := 0
+	renew := func(context.Context) (time.Duration, error) {
+		renewals++
+		return 0, nil
+	}
+
+	s := NewRenewalScheduler(renew, 0.5, &ZeroBackOff{})
+	if err := s.Run(context.Background(), time.Millisecond); err != ErrInvalidTTL {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if renewals != 1 {
+		t.Errorf("invalid number of renewals: %d", renewals)
+	}
+}
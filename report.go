@@ -0,0 +1,92 @@
+package backoff
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Report is the full picture of a retry loop that gave up, for support
+// bundles and bug reports. It can be serialized to JSON.
+type Report struct {
+	// Name is the name given with WithOperationName.
+	Name string
+	// Err is the error returned by the retry loop.
+	Err error
+	// Reason tells why the retry loop gave up, e.g. "backoff stopped".
+	Reason string
+	// Policy describes the state of the policy when the loop gave up, as
+	// returned by DebugState.
+	Policy string
+	// Attempts is the history of the last 100 attempts.
+	Attempts []AttemptRecord
+}
+
+// WithReport calls f with a Report when the retry loop gives up, including
+// when the operation returned a permanent error or the context was
+// canceled.
+func WithReport(f func(r *Report)) RetryOption {
+	return func(o *retryOptions) {
+		o.recordHistory = true
+		o.giveUps = append(o.giveUps, func(err error, history []AttemptRecord) error {
+			f(&Report{
+				Name:     o.name,
+				Err:      err,
+				Reason:   o.reason,
+				Policy:   DebugState(o.policy),
+				Attempts: append([]AttemptRecord(nil), history...),
+			})
+			return err
+		})
+	}
+}
+
+type jsonReport struct {
+	Name     string        `json:"name,omitempty"`
+	Error    string        `json:"error"`
+	Reason   string        `json:"reason"`
+	Policy   string        `json:"policy"`
+	Attempts []jsonAttempt `json:"attempts"`
+}
+
+type jsonAttempt struct {
+	Number         int       `json:"number"`
+	ID             string    `json:"id,omitempty"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	Start          time.Time `json:"start"`
+	Duration       string    `json:"duration"`
+	Next           string    `json:"next,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes r with errors as strings and durations in the format
+// of time.Duration.String.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	j := jsonReport{
+		Name:     r.Name,
+		Error:    errorString(r.Err),
+		Reason:   r.Reason,
+		Policy:   r.Policy,
+		Attempts: make([]jsonAttempt, len(r.Attempts)),
+	}
+	for i, a := range r.Attempts {
+		j.Attempts[i] = jsonAttempt{
+			Number:         a.Attempt.Number,
+			ID:             a.Attempt.ID,
+			IdempotencyKey: a.Attempt.IdempotencyKey,
+			Start:          a.Start,
+			Duration:       a.Duration.String(),
+			Error:          errorString(a.Err),
+		}
+		if a.Next > 0 {
+			j.Attempts[i].Next = a.Next.String()
+		}
+	}
+	return json.Marshal(j)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
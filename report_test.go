@@ -0,0 +1,66 @@
+package backoff
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithReport(t *testing.T) {
+	var r *Report
+	f := func() error { return errors.New("error") }
+	err := Retry(f, WithMaxRetries(NewConstantBackOff(time.Millisecond), 2),
+		WithOperationName("op"), WithReport(func(report *Report) { r = report }))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if r == nil {
+		t.Fatal("report was not made")
+	}
+	if r.Name != "op" || r.Err != err || r.Reason != reasonStopped {
+		t.Errorf("invalid report: %+v", r)
+	}
+	if r.Policy != "WithMaxRetries{tries=2/2} -> ConstantBackOff{interval=1ms}" {
+		t.Errorf("invalid policy: %s", r.Policy)
+	}
+	if len(r.Attempts) != 3 {
+		t.Fatalf("invalid number of attempts: %d", len(r.Attempts))
+	}
+	if r.Attempts[0].Next != time.Millisecond || r.Attempts[2].Next != 0 {
+		t.Errorf("invalid intervals: %+v", r.Attempts)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Name     string
+		Error    string
+		Attempts []struct {
+			Number int
+			Next   string
+			Error  string
+		}
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "op" || decoded.Error != "error" || len(decoded.Attempts) != 3 {
+		t.Errorf("invalid JSON: %s", b)
+	}
+	if a := decoded.Attempts[1]; a.Number != 2 || a.Next != "1ms" || a.Error != "error" {
+		t.Errorf("invalid JSON attempt: %+v", a)
+	}
+	if strings.Contains(string(b), `"next":""`) {
+		t.Errorf("empty interval is not omitted: %s", b)
+	}
+}
+
+func TestWithReportSucceeded(t *testing.T) {
+	Retry(func() error { return nil }, &ZeroBackOff{}, WithReport(func(*Report) {
+		t.Error("report made for a successful retry loop")
+	}))
+}
@@ -1,11 +1,48 @@
 package backoff
 
-import "time"
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
 
 // An Operation is executing by Retry() or RetryNotify().
 // The operation will be retried using a backoff policy if it returns an error.
 type Operation func() error
 
+// A ContextOperation is executed by RetryContext(). It receives a context
+// carrying information about the current attempt, which is canceled when
+// the retry loop returns.
+type ContextOperation func(ctx context.Context) error
+
+// An AttemptOperation is like an Operation but receives information about
+// the current attempt. It is executed by RetryAttempt().
+type AttemptOperation func(Attempt) error
+
+// Attempt describes a single call of an operation by a retry loop.
+type Attempt struct {
+	// Number is the number of the attempt, starting at 1.
+	Number int
+	// IdempotencyKey is the key generated by the function given with
+	// WithIdempotencyKey. It is the same for all attempts of a retry loop.
+	IdempotencyKey string
+	// ID identifies the attempt. It is generated by the function given
+	// with WithAttemptID, and empty otherwise.
+	ID string
+	// Repeats is the number of consecutive attempts, up to the previous
+	// one, that failed with the same error. See WithFingerprint.
+	Repeats int
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the Attempt carried by the context passed to a
+// ContextOperation.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(Attempt)
+	return a, ok
+}
+
 // Notify is a notify-on-error function. It receives an operation error and
 // backoff delay if the operation failed (with an error).
 //
@@ -20,41 +57,179 @@ type Notify func(error, time.Duration)
 // If o returns a *PermanentError, the operation is not retried, and the
 // wrapped error is returned.
 //
+// If the error returned by o has a RetryAfter() time.Duration method, Retry
+// waits at least the duration it returns before retrying. If it has a
+// Temporary() bool method returning false, such as a net.Error, it is
+// returned without retrying; see WithoutTemporaryCheck.
+//
 // Retry sleeps the goroutine for the duration returned by BackOff after a
 // failed operation returns.
-func Retry(o Operation, b BackOff) error { return RetryNotify(o, b, nil) }
+func Retry(o Operation, b BackOff, opts ...RetryOption) error {
+	return RetryNotify(o, b, nil, opts...)
+}
 
 // RetryNotify calls notify function with the error and wait duration
 // for each failed attempt before sleep.
-func RetryNotify(operation Operation, b BackOff, notify Notify) error {
+func RetryNotify(operation Operation, b BackOff, notify Notify, opts ...RetryOption) error {
+	return retry(func(context.Context) error { return operation() }, b, notify, opts)
+}
+
+// RetryAttempt is like Retry but passes information about each attempt to
+// the operation.
+func RetryAttempt(operation AttemptOperation, b BackOff, opts ...RetryOption) error {
+	return retry(func(ctx context.Context) error {
+		a, _ := AttemptFromContext(ctx)
+		return operation(a)
+	}, b, nil, opts)
+}
+
+// RetryContext is like Retry but the operation receives a context derived
+// from ctx. The context carries the current Attempt, which can be retrieved
+// with AttemptFromContext, and it is canceled when RetryContext returns.
+//
+// Retrying stops when ctx is canceled. ctx replaces the context b may have
+// been bound to with WithContext.
+func RetryContext(ctx context.Context, operation ContextOperation, b BackOff, opts ...RetryOption) error {
+	return retry(operation, WithContext(b, ctx), nil, opts)
+}
+
+func retry(operation ContextOperation, b BackOff, notify Notify, opts []RetryOption) error {
+	o := newRetryOptions(opts)
+	o.policy = b
+	o.start()
+	err := retryLoop(operation, b, notify, o)
+	if err != nil {
+		err = o.giveUp(err)
+	}
+	o.finish(err)
+	return err
+}
+
+func retryLoop(operation ContextOperation, b BackOff, notify Notify, o *retryOptions) error {
 	var err error
 	var next time.Duration
+	var prefetched bool
 
 	cb := ensureContext(b)
+	parent := cb.Context()
+	if o.stopBehavior == FinishAttempt {
+		parent = detachedContext{parent}
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if o.totalTimeout > 0 {
+		o.deadline = time.Now().Add(o.totalTimeout)
+		ctx, cancel = context.WithDeadline(parent, o.deadline)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	defer cancel()
+	o.drain(ctx, cancel)
+
+	a := Attempt{}
+	if o.idempotencyKey != nil {
+		a.IdempotencyKey = o.idempotencyKey()
+	}
 
 	b.Reset()
 	for {
-		if err = operation(); err == nil {
-			return nil
+		perr := o.prepare(ctx)
+		if perr == nil {
+			a.Number++
+			a.Repeats = o.streak.repeats
+			if o.attemptID != nil {
+				a.ID = o.attemptID()
+			}
+			o.beforeAttempt(a)
+			if o.attemptTimeout != nil {
+				// The timeout depends on the delay after the attempt.
+				next, prefetched = b.NextBackOff(), true
+			}
+			actx, end := o.attemptContext(ctx, a, next)
+			err = operation(actx)
+			end(err)
+			o.afterAttempt(a, err)
+			if err == nil {
+				o.reason = reasonSucceeded
+				return nil
+			}
+		} else if perr != errUnhealthy {
+			// A failed health check keeps the error of the last attempt.
+			err = perr
 		}
 
 		if permanent, ok := err.(*PermanentError); ok {
+			o.reason = reasonPermanent
 			return permanent.Err
 		}
+		if o.permanentByHint(err) {
+			o.reason = reasonPermanent
+			return err
+		}
+		o.failed(err)
+
+		if cerr := o.cleanup(err); cerr != nil {
+			o.reason = reasonPermanent
+			return cerr
+		}
+
+		if reason := o.escalate(); reason != "" {
+			o.reason = reason
+			return err
+		}
+
+		if o.isShutdown() {
+			o.reason = reasonShutdown
+			return err
+		}
 
-		if next = b.NextBackOff(); next == Stop {
+		if !prefetched {
+			next = b.NextBackOff()
+		}
+		prefetched = false
+
+		if next == Stop {
+			o.reason = reasonStopped
+			if ctx.Err() != nil || cb.Context().Err() != nil {
+				o.reason = o.doneReason(cb.Context(), ctx)
+			}
+			return err
+		}
+		next = pushback(err, next)
+
+		if o.exceedsDeadline(next) {
+			o.reason = reasonTotalTimeout
 			return err
 		}
 
 		if notify != nil {
 			notify(err, next)
 		}
+		o.wait(next)
 
-		t := time.NewTimer(next)
+		sleep := o.yield(sleepDuration(next))
+		if sleep < o.spinThreshold {
+			if reason := o.spin(cb.Context(), ctx, sleep); reason != "" {
+				o.reason = reason
+				return err
+			}
+			continue
+		}
+
+		t := time.NewTimer(sleep)
 
 		select {
 		case <-cb.Context().Done():
 			t.Stop()
+			o.reason = cb.Context().Err().Error()
+			return err
+		case <-ctx.Done():
+			t.Stop()
+			o.reason = o.doneReason(cb.Context(), ctx)
+			return err
+		case <-o.shutdown:
+			t.Stop()
+			o.reason = reasonShutdown
 			return err
 		case <-t.C:
 		}
@@ -97,3 +97,104 @@ func TestRetryPermenent(t *testing.T) {
 		t.Errorf("invalid number of retries: %d", i)
 	}
 }
+
+func TestRetryAttemptIdempotencyKey(t *testing.T) {
+	var n = 0
+	gen := func() string {
+		n++
+		return fmt.Sprintf("key-%d", n)
+	}
+
+	var attempts []Attempt
+	f := func(a Attempt) error {
+		attempts = append(attempts, a)
+		if a.Number == 3 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	err := RetryAttempt(f, &ZeroBackOff{}, WithIdempotencyKey(gen))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("invalid number of retries: %d", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.Number != i+1 || a.IdempotencyKey != "key-1" {
+			t.Errorf("invalid attempt: %+v", a)
+		}
+	}
+
+	RetryAttempt(f, &StopBackOff{}, WithIdempotencyKey(gen))
+	if attempts[3].IdempotencyKey != "key-2" {
+		t.Errorf("key is not generated per retry loop: %s", attempts[3].IdempotencyKey)
+	}
+}
+
+func TestRetryAttemptHooks(t *testing.T) {
+	var events []string
+	before := func(a Attempt) {
+		events = append(events, fmt.Sprintf("before %d", a.Number))
+	}
+	after := func(a Attempt, err error) {
+		events = append(events, fmt.Sprintf("after %d: %v", a.Number, err))
+	}
+
+	f := func(a Attempt) error {
+		events = append(events, fmt.Sprintf("attempt %d", a.Number))
+		if a.Number == 2 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	err := RetryAttempt(f, &ZeroBackOff{}, WithBeforeAttempt(before), WithAfterAttempt(after))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	expected := []string{"before 1", "attempt 1", "after 1: error", "before 2", "attempt 2", "after 2: <nil>"}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("invalid events: %q", events)
+	}
+}
+
+func TestRetryContextOperation(t *testing.T) {
+	var ctxs []context.Context
+	f := func(ctx context.Context) error {
+		ctxs = append(ctxs, ctx)
+		a, ok := AttemptFromContext(ctx)
+		if !ok || a.Number != len(ctxs) {
+			t.Errorf("invalid attempt: %+v", a)
+		}
+		if ctx.Err() != nil {
+			t.Error("context is canceled during attempt")
+		}
+		if a.Number == 3 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	err := RetryContext(context.Background(), f, NewConstantBackOff(time.Millisecond))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if len(ctxs) != 3 {
+		t.Fatalf("invalid number of retries: %d", len(ctxs))
+	}
+	if ctxs[2].Err() != context.Canceled {
+		t.Error("context is not canceled after retry loop returned")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f = func(context.Context) error {
+		cancel()
+		return errors.New("canceled")
+	}
+	if err = RetryContext(ctx, f, &ZeroBackOff{}); err == nil || err.Error() != "canceled" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
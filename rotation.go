@@ -0,0 +1,98 @@
+package backoff
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoEndpoints is returned by RetryOver when there are no endpoints.
+var ErrNoEndpoints = errors.New("backoff: no endpoints")
+
+// RetryOver retries op over n endpoints, calling it with the index of the
+// endpoint to use for each attempt. strategy picks the endpoint of every
+// attempt, so that failing endpoints are rotated or failed over between
+// retries. The rest of the behavior is the same as Retry. If n is not
+// positive, RetryOver returns ErrNoEndpoints without calling op.
+//
+//	endpoints := []string{"10.0.0.1:80", "10.0.0.2:80"}
+//	rotation := backoff.RoundRobin()
+//	err := backoff.RetryOver(len(endpoints), func(i int) error {
+//		return call(endpoints[i])
+//	}, backoff.NewExponentialBackOff(), rotation)
+func RetryOver(n int, op func(i int) error, b BackOff, strategy RotationStrategy, opts ...RetryOption) error {
+	if n <= 0 {
+		return ErrNoEndpoints
+	}
+	return Retry(func() error {
+		i := strategy.Pick(n)
+		err := op(i)
+		if err != nil {
+			strategy.Failed(i)
+		}
+		return err
+	}, b, opts...)
+}
+
+// RotationStrategy chooses among endpoints for RetryOver.
+// A strategy may be shared by several RetryOver calls to keep its state
+// across them. Implementations must be safe for concurrent use.
+type RotationStrategy interface {
+	// Pick returns the index of the endpoint to use among n endpoints, or
+	// -1 if n is not positive.
+	Pick(n int) int
+	// Failed is called when an attempt on endpoint i failed.
+	Failed(i int)
+}
+
+// RoundRobin returns a RotationStrategy that uses the endpoints in turn on
+// every attempt.
+func RoundRobin() RotationStrategy {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (r *roundRobin) Pick(n int) int {
+	if n <= 0 {
+		return -1
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.next % n
+	r.next = i + 1
+	return i
+}
+
+func (r *roundRobin) Failed(i int) {}
+
+// Sticky returns a RotationStrategy that keeps using the same endpoint until
+// an attempt on it fails, and then moves to the next one.
+func Sticky() RotationStrategy {
+	return &sticky{}
+}
+
+type sticky struct {
+	mu      sync.Mutex
+	current int
+}
+
+func (s *sticky) Pick(n int) int {
+	if n <= 0 {
+		return -1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current %= n
+	return s.current
+}
+
+func (s *sticky) Failed(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i == s.current {
+		s.current++
+	}
+}
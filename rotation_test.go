@@ -0,0 +1,60 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRetryOver(t *testing.T) {
+	down := map[int]bool{0: true, 1: true}
+	var used []int
+	op := func(i int) error {
+		used = append(used, i)
+		if down[i] {
+			return errors.New("down")
+		}
+		return nil
+	}
+
+	sticky := Sticky()
+	if err := RetryOver(3, op, &ZeroBackOff{}, sticky); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := RetryOver(3, op, &ZeroBackOff{}, sticky); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	// The second retry loop sticks to the endpoint that worked.
+	if fmt.Sprint(used) != "[0 1 2 2]" {
+		t.Errorf("invalid endpoints: %v", used)
+	}
+
+	used = nil
+	rotation := RoundRobin()
+	RetryOver(3, op, &ZeroBackOff{}, rotation)
+	RetryOver(3, op, &ZeroBackOff{}, rotation)
+	if fmt.Sprint(used) != "[0 1 2 0 1 2]" {
+		t.Errorf("invalid endpoints: %v", used)
+	}
+
+	used = nil
+	err := RetryOver(2, op, WithMaxRetries(&ZeroBackOff{}, 2), RoundRobin())
+	if err == nil || fmt.Sprint(used) != "[0 1 0]" {
+		t.Errorf("unexpected error %v or endpoints %v", err, used)
+	}
+}
+
+func TestRetryOverNoEndpoints(t *testing.T) {
+	for _, strategy := range []RotationStrategy{RoundRobin(), Sticky()} {
+		if i := strategy.Pick(0); i != -1 {
+			t.Errorf("%T: picked %d among no endpoints", strategy, i)
+		}
+		err := RetryOver(0, func(int) error {
+			t.Error("called without endpoints")
+			return nil
+		}, &ZeroBackOff{}, strategy)
+		if err != ErrNoEndpoints {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
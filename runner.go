@@ -0,0 +1,32 @@
+package backoff
+
+import "golang.org/x/net/context"
+
+// Runner is implemented by the long-lived types of this package, so that
+// they plug uniformly into application lifecycle managers.
+type Runner interface {
+	// Start ties the runner to ctx: it stops when ctx is canceled.
+	Start(ctx context.Context)
+	// Close stops the runner and waits until it has stopped.
+	Close() error
+	// Healthy reports whether the runner is running normally.
+	Healthy() bool
+}
+
+var (
+	_ Runner = (*Ticker)(nil)
+	_ Runner = (*PacedTicker)(nil)
+	_ Runner = (*Broadcaster)(nil)
+	_ Runner = (*RenewalScheduler)(nil)
+)
+
+// stopOnCancel calls stop when ctx is canceled before done is closed.
+func stopOnCancel(ctx context.Context, stop func(), done <-chan struct{}) {
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+		case <-done:
+		}
+	}()
+}
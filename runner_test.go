@@ -0,0 +1,81 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestRunnerTickers(t *testing.T) {
+	runners := map[string]Runner{
+		"Ticker":      NewTicker(NewConstantBackOff(time.Millisecond)),
+		"PacedTicker": NewPacedTicker(NewConstantBackOff(time.Millisecond)),
+		"Broadcaster": NewBroadcaster(NewConstantBackOff(time.Millisecond)),
+	}
+	for name, r := range runners {
+		ctx, cancel := context.WithCancel(context.Background())
+		r.Start(ctx)
+		if !r.Healthy() {
+			t.Errorf("%s: not healthy", name)
+		}
+		cancel()
+		if err := r.Close(); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+		if r.Healthy() {
+			t.Errorf("%s: healthy after Close", name)
+		}
+	}
+}
+
+func TestRunnerTickerStoppedByContext(t *testing.T) {
+	ticker := NewTicker(NewConstantBackOff(time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker.Start(ctx)
+	cancel()
+	for range ticker.C {
+	}
+	if ticker.Healthy() {
+		t.Error("healthy after its context was canceled")
+	}
+}
+
+func TestRenewalSchedulerRunner(t *testing.T) {
+	renewed := make(chan struct{}, 10)
+	s := NewRenewalScheduler(func(ctx context.Context) (time.Duration, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		renewed <- struct{}{}
+		return time.Hour, nil
+	}, 0.5, &ZeroBackOff{})
+
+	s.Start(context.Background())
+	select {
+	case <-renewed:
+	case <-time.After(time.Second):
+		t.Fatal("not renewed")
+	}
+	if !s.Healthy() {
+		t.Error("not healthy")
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if s.Healthy() {
+		t.Error("healthy after Close")
+	}
+
+	s = NewRenewalScheduler(func(context.Context) (time.Duration, error) {
+		return 0, errors.New("error")
+	}, 0.5, &StopBackOff{})
+	s.Start(context.Background())
+	for s.Healthy() {
+		time.Sleep(time.Millisecond)
+	}
+	if err := s.Close(); err == nil || err.Error() != "error" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package backoff
+
+import "math/rand"
+
+// sampleIntn is replaced in tests.
+var sampleIntn = rand.Intn
+
+// WithSampling applies opts to about one in n retry loops and ignores them
+// for the others. It keeps the cost of detailed instrumentation, such as
+// WithTrace, WithProgress or attempt spans, below the cost of retrying on
+// hot paths, while counters given outside of WithSampling, such as the
+// expvar counters and observers, remain exact:
+//
+//	err := backoff.Retry(op, b,
+//		backoff.WithOperationName("fetch"),
+//		backoff.WithSampling(100, backoff.WithTrace(os.Stderr)),
+//	)
+//
+// The sampling decision is made once per retry loop, so a sampled retry loop
+// is instrumented from the first attempt to the last. opts are applied to
+// every retry loop if n is less than 2.
+func WithSampling(n int, opts ...RetryOption) RetryOption {
+	return func(o *retryOptions) {
+		if n > 1 && sampleIntn(n) != 0 {
+			return
+		}
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
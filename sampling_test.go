@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWithSampling(t *testing.T) {
+	defer func(f func(int) int) { sampleIntn = f }(sampleIntn)
+	i := 0
+	sampleIntn = func(n int) int {
+		i++
+		return i % n
+	}
+
+	var buf bytes.Buffer
+	attempts := 0
+	f := func() error { return errors.New("error") }
+	for j := 0; j < 6; j++ {
+		Retry(f, WithMaxRetries(&ZeroBackOff{}, 1),
+			WithAfterAttempt(func(Attempt, error) { attempts++ }),
+			WithSampling(3, WithTrace(&buf)),
+		)
+	}
+
+	if attempts != 12 {
+		t.Errorf("unsampled hooks were not called for every attempt: %d", attempts)
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("attempt 1\n")); n != 2 {
+		t.Errorf("invalid number of sampled retry loops: %d", n)
+	}
+}
+
+func TestWithSamplingAlways(t *testing.T) {
+	var buf bytes.Buffer
+	Retry(func() error { return nil }, &ZeroBackOff{}, WithSampling(1, WithTrace(&buf)))
+	if buf.Len() == 0 {
+		t.Error("retry loop was not traced")
+	}
+}
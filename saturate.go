@@ -0,0 +1,43 @@
+package backoff
+
+import (
+	"math"
+	"time"
+)
+
+// SaturatingAdd returns a + b, or the largest or smallest duration if the sum
+// overflows time.Duration. Custom policies can use it to accumulate
+// intervals without wrapping around to negative values, which would be
+// misread as Stop.
+func SaturatingAdd(a, b time.Duration) time.Duration {
+	sum := a + b
+	if b > 0 && sum < a {
+		return math.MaxInt64
+	}
+	if b < 0 && sum > a {
+		return math.MinInt64
+	}
+	return sum
+}
+
+// SaturatingMul returns d multiplied by factor, or the largest or smallest
+// duration if the product overflows time.Duration. It returns 0 if factor
+// is NaN.
+func SaturatingMul(d time.Duration, factor float64) time.Duration {
+	return durationFromFloat(float64(d) * factor)
+}
+
+// durationFromFloat converts f to a duration, saturating at the bounds of
+// time.Duration, which unlike a plain conversion never wraps around.
+func durationFromFloat(f float64) time.Duration {
+	switch {
+	case f != f:
+		return 0
+	// float64(math.MaxInt64) rounds up to 1<<63, which does not fit.
+	case f >= 1<<63:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return time.Duration(f)
+}
@@ -0,0 +1,44 @@
+package backoff
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSaturatingAdd(t *testing.T) {
+	cases := []struct{ a, b, sum time.Duration }{
+		{1, 2, 3},
+		{-1, 2, 1},
+		{math.MaxInt64, 1, math.MaxInt64},
+		{math.MaxInt64 / 2, math.MaxInt64, math.MaxInt64},
+		{math.MinInt64, -1, math.MinInt64},
+		{math.MaxInt64, math.MinInt64, -1},
+	}
+	for _, c := range cases {
+		if sum := SaturatingAdd(c.a, c.b); sum != c.sum {
+			t.Errorf("SaturatingAdd(%d, %d) = %d, want %d", c.a, c.b, sum, c.sum)
+		}
+	}
+}
+
+func TestSaturatingMul(t *testing.T) {
+	cases := []struct {
+		d       time.Duration
+		factor  float64
+		product time.Duration
+	}{
+		{time.Second, 1.5, 1500 * time.Millisecond},
+		{time.Second, -2, -2 * time.Second},
+		{math.MaxInt64, 1, math.MaxInt64},
+		{math.MaxInt64 / 2, 10, math.MaxInt64},
+		{math.MaxInt64, -10, math.MinInt64},
+		{time.Second, math.Inf(1), math.MaxInt64},
+		{time.Second, math.NaN(), 0},
+	}
+	for _, c := range cases {
+		if p := SaturatingMul(c.d, c.factor); p != c.product {
+			t.Errorf("SaturatingMul(%d, %v) = %d, want %d", c.d, c.factor, p, c.product)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+WithTimeScale creates a wrapper around another BackOff, which multiplies
+every interval of the wrapped policy by factor, e.g. 0.01 for fast tests or
+2.0 to slow down an over-eager client. Stop is returned unchanged.
+
+Note: Implementation is not thread-safe.
+*/
+func WithTimeScale(b BackOff, factor float64) BackOff {
+	return &backOffScale{delegate: b, factor: factor}
+}
+
+type backOffScale struct {
+	delegate BackOff
+	factor   float64
+}
+
+func (b *backOffScale) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		return Stop
+	}
+	return scaleDuration(next, b.factor)
+}
+
+func (b *backOffScale) Reset() {
+	b.delegate.Reset()
+}
+
+func (b *backOffScale) String() string {
+	return fmt.Sprintf("WithTimeScale{factor=%v}", b.factor)
+}
+
+func (b *backOffScale) Unwrap() BackOff {
+	return b.delegate
+}
+
+// scaleDuration multiplies d by factor, saturating at the maximum duration.
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	scaled := SaturatingMul(d, factor)
+	if scaled < 0 {
+		return 0
+	}
+	return scaled
+}
@@ -0,0 +1,18 @@
+package backoff
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWithTimeScale(t *testing.T) {
+	b := WithTimeScale(WithMaxRetries(NewConstantBackOff(time.Second), 2), 0.5)
+	assertSequence(t, b, 500*time.Millisecond, 500*time.Millisecond, Stop)
+
+	b.Reset()
+	assertEquals(t, 500*time.Millisecond, b.NextBackOff())
+
+	b = WithTimeScale(NewConstantBackOff(math.MaxInt64/2), 10)
+	assertEquals(t, math.MaxInt64, b.NextBackOff())
+}
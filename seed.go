@@ -0,0 +1,36 @@
+package backoff
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// SetSeed derives the jitter of b from seed instead of a random source. The
+// source is seeded again on every Reset, so that each retry loop of b
+// follows the same schedule, which is reproducible across restarts of the
+// process. Use different seeds for different instances, such as the one
+// returned by HostSeed, so that a fleet of clients remains spread out.
+func (b *ExponentialBackOff) SetSeed(seed int64) {
+	b.seed, b.seeded = seed, true
+	b.random = rand.New(rand.NewSource(seed))
+}
+
+// SeedFrom returns a seed derived from a stable identifier of an instance,
+// such as a pod name.
+func SeedFrom(id string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return int64(h.Sum64())
+}
+
+// HostSeed returns a seed derived from the host name, or a random seed if
+// the host name is not available.
+func HostSeed() int64 {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return time.Now().UnixNano()
+	}
+	return SeedFrom(name)
+}
@@ -0,0 +1,49 @@
+package backoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func seededIntervals(seed int64) []time.Duration {
+	b := NewExponentialBackOff()
+	b.SetSeed(seed)
+	b.Reset()
+	d := make([]time.Duration, 5)
+	for i := range d {
+		d[i] = b.NextBackOff()
+	}
+	return d
+}
+
+func TestSetSeed(t *testing.T) {
+	a, b := seededIntervals(1), seededIntervals(1)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("schedules with the same seed differ: %v, %v", a, b)
+	}
+	if c := seededIntervals(2); reflect.DeepEqual(a, c) {
+		t.Errorf("schedules with different seeds are the same: %v", c)
+	}
+
+	exp := NewExponentialBackOff()
+	exp.SetSeed(1)
+	exp.Reset()
+	first := exp.NextBackOff()
+	exp.Reset()
+	if d := exp.NextBackOff(); d != first {
+		t.Errorf("schedule is not repeated after Reset: %v, %v", d, first)
+	}
+}
+
+func TestSeedFrom(t *testing.T) {
+	if SeedFrom("pod-1") != SeedFrom("pod-1") {
+		t.Error("seed is not stable")
+	}
+	if SeedFrom("pod-1") == SeedFrom("pod-2") {
+		t.Error("seeds of different instances are the same")
+	}
+	if HostSeed() != HostSeed() {
+		t.Error("host seed is not stable")
+	}
+}
@@ -0,0 +1,69 @@
+package backoff
+
+import "time"
+
+// A Sequence is a backoff policy like BackOff, but it reports the end of
+// retries with a separate boolean instead of the Stop duration. New policies
+// should prefer implementing Sequence, because a negative duration computed
+// by mistake cannot be misread as Stop. Use FromSequence to pass a Sequence
+// to Retry and Ticker.
+type Sequence interface {
+	// Next returns the duration to wait before retrying the operation and
+	// true, or false if no more retries should be made.
+	Next() (time.Duration, bool)
+
+	// Reset to initial state.
+	Reset()
+}
+
+// FromSequence returns a BackOff that returns the durations of s, and Stop
+// once s returns false. Negative durations returned by s are treated as 0.
+func FromSequence(s Sequence) BackOff {
+	if a, ok := s.(*sequenceAdapter); ok {
+		return a.b
+	}
+	return &backOffSequence{s: s}
+}
+
+// AsSequence returns a Sequence that returns the durations of b, and false
+// once b returns Stop. Other negative durations are treated as 0.
+func AsSequence(b BackOff) Sequence {
+	if a, ok := b.(*backOffSequence); ok {
+		return a.s
+	}
+	return &sequenceAdapter{b: b}
+}
+
+type backOffSequence struct {
+	s Sequence
+}
+
+func (b *backOffSequence) NextBackOff() time.Duration {
+	next, ok := b.s.Next()
+	if !ok {
+		return Stop
+	}
+	if next < 0 {
+		return 0
+	}
+	return next
+}
+
+func (b *backOffSequence) Reset() { b.s.Reset() }
+
+type sequenceAdapter struct {
+	b BackOff
+}
+
+func (s *sequenceAdapter) Next() (time.Duration, bool) {
+	next := s.b.NextBackOff()
+	if next == Stop {
+		return 0, false
+	}
+	if next < 0 {
+		return 0, true
+	}
+	return next, true
+}
+
+func (s *sequenceAdapter) Reset() { s.b.Reset() }
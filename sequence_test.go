@@ -0,0 +1,50 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+type countdown struct {
+	n, i int
+}
+
+func (c *countdown) Next() (time.Duration, bool) {
+	if c.i >= c.n {
+		return 0, false
+	}
+	c.i++
+	// Intervals go negative, which a BackOff would misreport as Stop.
+	return time.Duration(1 - c.i), true
+}
+
+func (c *countdown) Reset() { c.i = 0 }
+
+func TestFromSequence(t *testing.T) {
+	b := FromSequence(&countdown{n: 3})
+	assertSequence(t, b, 0, 0, 0, Stop)
+	b.Reset()
+	assertEquals(t, 0, b.NextBackOff())
+
+	if _, ok := AsSequence(b).(*countdown); !ok {
+		t.Error("adapter is not unwrapped")
+	}
+}
+
+func TestAsSequence(t *testing.T) {
+	s := AsSequence(scripted(2, -5))
+	expected := []struct {
+		d  time.Duration
+		ok bool
+	}{{2, true}, {0, true}, {0, false}}
+	for i, e := range expected {
+		if d, ok := s.Next(); d != e.d || ok != e.ok {
+			t.Errorf("%d: got %v %v, expected %v %v", i, d, ok, e.d, e.ok)
+		}
+	}
+
+	b := NewConstantBackOff(time.Second)
+	if FromSequence(AsSequence(b)) != b {
+		t.Error("adapter is not unwrapped")
+	}
+}
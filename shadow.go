@@ -0,0 +1,46 @@
+package backoff
+
+import "time"
+
+/*
+WithShadow creates a wrapper around another BackOff, which also runs the
+candidate policy in shadow and records the intervals it would have chosen in
+the returned Stats. The intervals of b are returned unchanged. It helps to
+evaluate a new tuning in production before switching to it:
+
+	active, activeStats := backoff.WithStats(current)
+	b, shadowStats := backoff.WithShadow(active, candidate)
+	err := backoff.Retry(op, b)
+	log.Println(activeStats.Total(), shadowStats.Total())
+
+The candidate is asked for an interval at each step until it stops, and it is
+Reset along with b.
+
+Note: Implementation is not thread-safe.
+*/
+func WithShadow(b, candidate BackOff) (BackOff, *Stats) {
+	shadow, stats := WithStats(candidate)
+	return &backOffShadow{delegate: b, shadow: shadow, stats: stats}, stats
+}
+
+type backOffShadow struct {
+	delegate BackOff
+	shadow   BackOff
+	stats    *Stats
+}
+
+func (b *backOffShadow) NextBackOff() time.Duration {
+	if !b.stats.Stopped() {
+		b.shadow.NextBackOff()
+	}
+	return b.delegate.NextBackOff()
+}
+
+func (b *backOffShadow) Reset() {
+	b.shadow.Reset()
+	b.delegate.Reset()
+}
+
+func (b *backOffShadow) Unwrap() BackOff {
+	return b.delegate
+}
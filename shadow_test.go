@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithShadow(t *testing.T) {
+	b, stats := WithShadow(scripted(1, 2, 3), scripted(10, 20))
+
+	assertSequence(t, b, 1, 2, 3, Stop)
+	if d := stats.Intervals(); !reflect.DeepEqual(d, []time.Duration{10, 20}) {
+		t.Errorf("invalid shadow intervals: %v", d)
+	}
+	if !stats.Stopped() {
+		t.Error("shadow policy did not stop")
+	}
+
+	b.Reset()
+	if stats.Attempts() != 0 || stats.Stopped() {
+		t.Error("shadow stats are not reset")
+	}
+	assertSequence(t, b, 1)
+	if d := stats.Intervals(); !reflect.DeepEqual(d, []time.Duration{10}) {
+		t.Errorf("invalid shadow intervals after Reset: %v", d)
+	}
+}
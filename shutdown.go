@@ -0,0 +1,54 @@
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// WithShutdown stops the retry loop gracefully when shutdown is closed, for
+// example with the Done channel of a context canceled on process shutdown.
+//
+// No further attempt is made after shutdown is closed and a pending sleep is
+// interrupted; the retry loop returns the last error of the operation. An
+// attempt that is running when shutdown is closed may finish within
+// drainTimeout, after which the context passed to a ContextOperation is
+// canceled.
+func WithShutdown(shutdown <-chan struct{}, drainTimeout time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.shutdown = shutdown
+		o.drainTimeout = drainTimeout
+	}
+}
+
+func (o *retryOptions) isShutdown() bool {
+	select {
+	case <-o.shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// drain cancels the attempts of the retry loop running in ctx once the loop
+// is shut down and drainTimeout is over.
+func (o *retryOptions) drain(ctx context.Context, cancel context.CancelFunc) {
+	if o.shutdown == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-o.shutdown:
+		case <-ctx.Done():
+			return
+		}
+
+		t := time.NewTimer(o.drainTimeout)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+}
@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestWithShutdownSleeping(t *testing.T) {
+	shutdown := make(chan struct{})
+	var i = 0
+	f := func() error {
+		i++
+		close(shutdown)
+		return errors.New("error")
+	}
+
+	start := time.Now()
+	err := Retry(f, NewConstantBackOff(time.Minute), WithShutdown(shutdown, time.Second))
+	if err == nil || err.Error() != "error" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if i != 1 {
+		t.Errorf("invalid number of retries: %d", i)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleep is not interrupted")
+	}
+}
+
+func TestWithShutdownDrain(t *testing.T) {
+	shutdown := make(chan struct{})
+	var finished, canceled bool
+
+	// The first attempt finishes within the drain timeout.
+	f := func(ctx context.Context) error {
+		close(shutdown)
+		select {
+		case <-time.After(10 * time.Millisecond):
+			finished = true
+		case <-ctx.Done():
+		}
+		return errors.New("error")
+	}
+	RetryContext(context.Background(), f, &ZeroBackOff{}, WithShutdown(shutdown, time.Second))
+	if !finished {
+		t.Error("attempt is not allowed to finish")
+	}
+
+	// The second one is canceled after the drain timeout.
+	shutdown = make(chan struct{})
+	f = func(ctx context.Context) error {
+		close(shutdown)
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			canceled = true
+		}
+		return errors.New("error")
+	}
+	RetryContext(context.Background(), f, &ZeroBackOff{}, WithShutdown(shutdown, 10*time.Millisecond))
+	if !canceled {
+		t.Error("attempt is not canceled after drain timeout")
+	}
+}
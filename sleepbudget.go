@@ -0,0 +1,47 @@
+package backoff
+
+import "time"
+
+/*
+WithMaxCumulativeSleep creates a wrapper around another BackOff, which stops
+before the sum of the intervals it returned since the last Reset would
+exceed max, and keeps returning Stop until the next Reset. Unlike the
+MaxElapsedTime of ExponentialBackOff, which counts wall-clock time
+including the attempts, it bounds the sleep time exactly, e.g. for queue
+consumers that are billed by it.
+
+Note: Implementation is not thread-safe.
+*/
+func WithMaxCumulativeSleep(b BackOff, max time.Duration) BackOff {
+	return &backOffSleepBudget{delegate: b, max: max}
+}
+
+type backOffSleepBudget struct {
+	delegate BackOff
+	max      time.Duration
+	total    time.Duration
+	stopped  bool
+}
+
+func (b *backOffSleepBudget) NextBackOff() time.Duration {
+	if b.stopped {
+		return Stop
+	}
+	next := b.delegate.NextBackOff()
+	if next == Stop || next > b.max-b.total {
+		b.stopped = true
+		return Stop
+	}
+	b.total += next
+	return next
+}
+
+func (b *backOffSleepBudget) Reset() {
+	b.total = 0
+	b.stopped = false
+	b.delegate.Reset()
+}
+
+func (b *backOffSleepBudget) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,26 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithMaxCumulativeSleep(t *testing.T) {
+	b := WithMaxCumulativeSleep(scripted(4, 3, 3, 1), 10)
+	assertSequence(t, b, 4, 3, 3, Stop)
+
+	b.Reset()
+	assertSequence(t, b, 4)
+
+	b = WithMaxCumulativeSleep(NewConstantBackOff(time.Second), 2500*time.Millisecond)
+	assertSequence(t, b, time.Second, time.Second, Stop)
+}
+
+func TestWithMaxCumulativeSleepStops(t *testing.T) {
+	// A shorter interval after the budget is exceeded is not returned.
+	b := WithMaxCumulativeSleep(scripted(4, 5, 1), 6)
+	assertSequence(t, b, 4, Stop, Stop)
+
+	b.Reset()
+	assertSequence(t, b, 4, Stop)
+}
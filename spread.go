@@ -0,0 +1,61 @@
+package backoff
+
+import (
+	"fmt"
+	"time"
+)
+
+// SpreadOver returns the offset of the client number index of a fleet of n
+// clients spread evenly over window, that is index/n of window. It panics
+// if index is not in the range [0, n).
+func SpreadOver(n, index int, window time.Duration) time.Duration {
+	if n <= 0 || index < 0 || index >= n {
+		panic(fmt.Sprintf("backoff: client index %d is not in [0, %d)", index, n))
+	}
+	return SaturatingMul(window, float64(index)/float64(n))
+}
+
+/*
+WithSpread creates a wrapper around another BackOff, which offsets the
+schedule of the client number index of a fleet of n clients by
+SpreadOver(n, index, window): the first interval after a Reset is extended
+by the offset, so that the following attempts of the clients stay apart.
+
+For fleets with a known membership, such as the replicas of a stateful set,
+it spreads the clients deterministically across the window when they all
+fail at once, which random jitter only does on average. The wrapped policy
+should then have no randomization of its own.
+
+Note: Implementation is not thread-safe.
+*/
+func WithSpread(b BackOff, n, index int, window time.Duration) BackOff {
+	return &backOffSpread{delegate: b, offset: SpreadOver(n, index, window)}
+}
+
+type backOffSpread struct {
+	delegate BackOff
+	offset   time.Duration
+	started  bool
+}
+
+func (b *backOffSpread) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	if next == Stop || b.started {
+		return next
+	}
+	b.started = true
+	return SaturatingAdd(next, b.offset)
+}
+
+func (b *backOffSpread) Reset() {
+	b.started = false
+	b.delegate.Reset()
+}
+
+func (b *backOffSpread) String() string {
+	return fmt.Sprintf("WithSpread{offset=%v}", b.offset)
+}
+
+func (b *backOffSpread) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,37 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpreadOver(t *testing.T) {
+	for index, expected := range []time.Duration{0, 15 * time.Second, 30 * time.Second, 45 * time.Second} {
+		assertEquals(t, expected, SpreadOver(4, index, time.Minute))
+	}
+	assertEquals(t, 0, SpreadOver(1, 0, time.Minute))
+}
+
+func TestSpreadOverPanics(t *testing.T) {
+	for _, c := range [][2]int{{0, 0}, {3, 3}, {3, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("SpreadOver(%d, %d): expected a panic", c[0], c[1])
+				}
+			}()
+			SpreadOver(c[0], c[1], time.Minute)
+		}()
+	}
+}
+
+func TestWithSpread(t *testing.T) {
+	b := WithSpread(scripted(time.Second, time.Second), 4, 3, time.Minute)
+	assertSequence(t, b, 46*time.Second, time.Second, Stop)
+
+	b.Reset()
+	assertSequence(t, b, 46*time.Second, time.Second)
+
+	b = WithSpread(&StopBackOff{}, 4, 3, time.Minute)
+	assertSequence(t, b, Stop)
+}
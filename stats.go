@@ -0,0 +1,175 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// WithStats creates a wrapper around another BackOff, which records the
+// intervals it returns in the returned Stats. The Stats are cleared when the
+// BackOff is Reset, so after a Retry they describe that retry loop.
+func WithStats(b BackOff) (BackOff, *Stats) {
+	s := &Stats{start: time.Now()}
+	return &backOffStats{delegate: b, stats: s}, s
+}
+
+type backOffStats struct {
+	delegate BackOff
+	stats    *Stats
+}
+
+func (b *backOffStats) NextBackOff() time.Duration {
+	next := b.delegate.NextBackOff()
+	b.stats.record(next)
+	return next
+}
+
+func (b *backOffStats) Reset() {
+	b.stats.reset()
+	b.delegate.Reset()
+}
+
+func (b *backOffStats) Unwrap() BackOff {
+	return b.delegate
+}
+
+// maxStatsIntervals is the number of intervals kept by Stats.
+const maxStatsIntervals = 1000
+
+// Stats holds the intervals returned by a BackOff created with WithStats.
+// It is safe to query Stats while the BackOff is in use.
+//
+// Attempts, Total, Min, Max and Mean cover all the intervals since the last
+// Reset, while only the last 1000 intervals are kept for Intervals and
+// Histogram, so that a policy that is never Reset, such as the one of a
+// long-lived Ticker, does not grow them without bound.
+type Stats struct {
+	mu        sync.Mutex
+	start     time.Time
+	intervals []time.Duration // ring buffer of the last intervals
+	count     int
+	total     time.Duration
+	min, max  time.Duration
+	stopped   bool
+	// latency is the moving average of the durations of successful
+	// attempts. It is not cleared by Reset.
+	latency time.Duration
+}
+
+func (s *Stats) record(next time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next == Stop {
+		s.stopped = true
+		return
+	}
+	if len(s.intervals) < maxStatsIntervals {
+		s.intervals = append(s.intervals, next)
+	} else {
+		s.intervals[s.count%maxStatsIntervals] = next
+	}
+	if s.count == 0 || next < s.min {
+		s.min = next
+	}
+	if next > s.max {
+		s.max = next
+	}
+	s.count++
+	s.total = SaturatingAdd(s.total, next)
+}
+
+func (s *Stats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start = time.Now()
+	s.intervals = nil
+	s.count, s.total, s.min, s.max = 0, 0, 0, 0
+	s.stopped = false
+}
+
+// Attempts returns the number of intervals returned since the last Reset,
+// which is the number of retries of the operation.
+func (s *Stats) Attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Intervals returns the intervals returned since the last Reset, in order,
+// up to the last 1000.
+func (s *Stats) Intervals() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	intervals := make([]time.Duration, 0, len(s.intervals))
+	if len(s.intervals) < maxStatsIntervals {
+		return append(intervals, s.intervals...)
+	}
+	// The oldest interval is the next one to be overwritten.
+	oldest := s.count % maxStatsIntervals
+	intervals = append(intervals, s.intervals[oldest:]...)
+	return append(intervals, s.intervals[:oldest]...)
+}
+
+// Stopped reports whether the BackOff returned Stop since the last Reset.
+func (s *Stats) Stopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+// Elapsed returns the time elapsed since the last Reset.
+func (s *Stats) Elapsed() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Since(s.start)
+}
+
+// Total returns the sum of the intervals.
+func (s *Stats) Total() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Min returns the shortest interval, or 0 if there are none.
+func (s *Stats) Min() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.min
+}
+
+// Max returns the longest interval, or 0 if there are none.
+func (s *Stats) Max() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.max
+}
+
+// Mean returns the average interval, or 0 if there are none.
+func (s *Stats) Mean() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0
+	}
+	return s.total / time.Duration(s.count)
+}
+
+// Histogram counts the intervals falling in the buckets delimited by bounds,
+// which must be sorted in increasing order. The i-th count is the number of
+// intervals less than or equal to bounds[i] and greater than bounds[i-1];
+// the last count is the number of intervals greater than all bounds. Like
+// Intervals, it covers the last 1000 intervals.
+func (s *Stats) Histogram(bounds []time.Duration) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make([]int, len(bounds)+1)
+	for _, d := range s.intervals {
+		i := 0
+		for i < len(bounds) && d > bounds[i] {
+			i++
+		}
+		counts[i]++
+	}
+	return counts
+}
@@ -0,0 +1,65 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWithStats(t *testing.T) {
+	b, stats := WithStats(scripted(3, 1, 2))
+
+	err := Retry(func() error { return errors.New("error") }, b)
+	if err == nil {
+		t.Error("error is unexpectedly nil")
+	}
+
+	if stats.Attempts() != 3 || !stats.Stopped() {
+		t.Errorf("invalid attempts %d or stopped %v", stats.Attempts(), stats.Stopped())
+	}
+	if fmt.Sprint(stats.Intervals()) != "[3ns 1ns 2ns]" {
+		t.Errorf("invalid intervals: %v", stats.Intervals())
+	}
+	assertEquals(t, 6, stats.Total())
+	assertEquals(t, 1, stats.Min())
+	assertEquals(t, 3, stats.Max())
+	assertEquals(t, 2, stats.Mean())
+	if stats.Elapsed() <= 0 || stats.Elapsed() > time.Second {
+		t.Errorf("invalid elapsed time: %v", stats.Elapsed())
+	}
+	if h := stats.Histogram([]time.Duration{1, 2}); fmt.Sprint(h) != "[1 1 1]" {
+		t.Errorf("invalid histogram: %v", h)
+	}
+
+	b.Reset()
+	if stats.Attempts() != 0 || stats.Stopped() || stats.Mean() != 0 {
+		t.Error("stats are not reset")
+	}
+}
+
+func TestStatsLimit(t *testing.T) {
+	_, stats := WithStats(&ZeroBackOff{})
+	n := maxStatsIntervals + 10
+	for i := 1; i <= n; i++ {
+		stats.record(time.Duration(i))
+	}
+
+	if a := stats.Attempts(); a != n {
+		t.Errorf("invalid attempts: %d", a)
+	}
+	assertEquals(t, 1, stats.Min())
+	assertEquals(t, time.Duration(n), stats.Max())
+	assertEquals(t, time.Duration(n*(n+1)/2), stats.Total())
+
+	// Only the last intervals are kept, in order.
+	intervals := stats.Intervals()
+	if len(intervals) != maxStatsIntervals {
+		t.Fatalf("invalid number of intervals kept: %d", len(intervals))
+	}
+	for i, d := range intervals {
+		if d != time.Duration(i+11) {
+			t.Fatalf("%d: invalid interval %v", i, d)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// StopBehavior tells what happens to a running attempt of a ContextOperation
+// when the context of the BackOff is canceled.
+type StopBehavior int
+
+const (
+	// CancelAttempt cancels the context of the running attempt.
+	// This is the default.
+	CancelAttempt StopBehavior = iota
+	// FinishAttempt lets the running attempt finish; its context only
+	// carries the values of the BackOff's context. The retry loop then
+	// returns without retrying.
+	FinishAttempt
+)
+
+// WithStopBehavior sets what happens to a running attempt when the context
+// of the BackOff is canceled. The total timeout set with WithTotalTimeout
+// cancels running attempts regardless of the behavior.
+func WithStopBehavior(sb StopBehavior) RetryOption {
+	return func(o *retryOptions) { o.stopBehavior = sb }
+}
+
+// detachedContext keeps the values of its parent but is never canceled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}             { return nil }
+func (c detachedContext) Err() error                        { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
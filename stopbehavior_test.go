@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type valueKey struct{}
+
+func TestWithStopBehavior(t *testing.T) {
+	for _, sb := range []StopBehavior{CancelAttempt, FinishAttempt} {
+		parent := context.WithValue(context.Background(), valueKey{}, "value")
+		ctx, cancel := context.WithCancel(parent)
+
+		var i = 0
+		var canceled bool
+		f := func(ctx context.Context) error {
+			i++
+			if ctx.Value(valueKey{}) != "value" {
+				t.Error("context values are lost")
+			}
+			cancel()
+			select {
+			case <-ctx.Done():
+				canceled = true
+			case <-time.After(10 * time.Millisecond):
+			}
+			return errors.New("error")
+		}
+
+		err := RetryContext(ctx, f, &ZeroBackOff{}, WithStopBehavior(sb))
+		if err == nil || i != 1 {
+			t.Errorf("%d: unexpected error %v after %d attempts", sb, err, i)
+		}
+		if canceled != (sb == CancelAttempt) {
+			t.Errorf("%d: attempt canceled: %v", sb, canceled)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package backoff
+
+import "time"
+
+// A StopCondition reports whether a policy wrapped with StopWhen should stop
+// instead of returning the upcoming interval. Conditions are reusable values
+// that combine with StopOnAny and StopOnAll, independently of the policy
+// generating the intervals.
+type StopCondition func(s State) bool
+
+// MaxAttempts stops once n attempts have failed, so that the operation is
+// attempted at most n times.
+func MaxAttempts(n int) StopCondition {
+	return func(s State) bool { return s.Attempts >= n }
+}
+
+// MaxElapsed stops if the next attempt would start more than d after the
+// last Reset.
+func MaxElapsed(d time.Duration) StopCondition {
+	return func(s State) bool { return s.Elapsed+s.Interval > d }
+}
+
+// BudgetExhausted stops if the intervals since the last Reset would add up
+// to more than budget, like WithMaxCumulativeSleep.
+func BudgetExhausted(budget time.Duration) StopCondition {
+	return func(s State) bool { return s.Interval > budget-s.Slept }
+}
+
+// StopOnAny stops as soon as one of conds is true. With no conditions, it
+// never stops.
+func StopOnAny(conds ...StopCondition) StopCondition {
+	return func(s State) bool {
+		for _, c := range conds {
+			if c(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// StopOnAll stops when all of conds are true. With no conditions, it never
+// stops.
+func StopOnAll(conds ...StopCondition) StopCondition {
+	return func(s State) bool {
+		for _, c := range conds {
+			if !c(s) {
+				return false
+			}
+		}
+		return len(conds) > 0
+	}
+}
@@ -0,0 +1,50 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopConditions(t *testing.T) {
+	s := State{Attempts: 3, Elapsed: 50 * time.Second, Interval: 5 * time.Second, Slept: 40 * time.Second}
+	cases := []struct {
+		name string
+		cond StopCondition
+		stop bool
+	}{
+		{"MaxAttempts(3)", MaxAttempts(3), true},
+		{"MaxAttempts(4)", MaxAttempts(4), false},
+		{"MaxElapsed(55s)", MaxElapsed(55 * time.Second), false},
+		{"MaxElapsed(54s)", MaxElapsed(54 * time.Second), true},
+		{"BudgetExhausted(45s)", BudgetExhausted(45 * time.Second), false},
+		{"BudgetExhausted(44s)", BudgetExhausted(44 * time.Second), true},
+		{"StopOnAny()", StopOnAny(), false},
+		{"StopOnAny(false, true)", StopOnAny(MaxAttempts(4), MaxAttempts(3)), true},
+		{"StopOnAny(false, false)", StopOnAny(MaxAttempts(4), MaxAttempts(5)), false},
+		{"StopOnAll()", StopOnAll(), false},
+		{"StopOnAll(true, true)", StopOnAll(MaxAttempts(3), MaxAttempts(2)), true},
+		{"StopOnAll(true, false)", StopOnAll(MaxAttempts(3), MaxAttempts(4)), false},
+	}
+	for _, c := range cases {
+		if stop := c.cond(s); stop != c.stop {
+			t.Errorf("%s: got %v, want %v", c.name, stop, c.stop)
+		}
+	}
+}
+
+func TestStopWhenConditions(t *testing.T) {
+	b := StopWhen(NewConstantBackOff(time.Second), StopOnAny(
+		MaxAttempts(5),
+		BudgetExhausted(2500*time.Millisecond),
+	))
+	assertSequence(t, b, time.Second, time.Second, Stop)
+
+	b.Reset()
+	assertSequence(t, b, time.Second, time.Second, Stop)
+
+	b = StopWhen(NewConstantBackOff(time.Second), StopOnAll(
+		MaxAttempts(3),
+		BudgetExhausted(time.Second),
+	))
+	assertSequence(t, b, time.Second, time.Second, Stop)
+}
@@ -0,0 +1,85 @@
+package backoff
+
+import "time"
+
+// State describes the progress of a policy wrapped with StopWhen when it is
+// about to return an interval.
+type State struct {
+	// Attempts is the number of intervals requested since the last Reset,
+	// including this one, which is the number of failed attempts.
+	Attempts int
+	// Elapsed is the time elapsed since the last Reset.
+	Elapsed time.Duration
+	// Interval is the interval the wrapped policy returned.
+	Interval time.Duration
+	// Slept is the sum of the intervals returned since the last Reset,
+	// excluding this one.
+	Slept time.Duration
+}
+
+/*
+StopWhen creates a wrapper around another BackOff, which returns Stop as soon
+as stop returns true for the upcoming interval. Arbitrary stopping conditions
+compose without writing a full BackOff, e.g. stop after 5 attempts or one
+minute, whichever comes first:
+
+	b = backoff.StopWhen(b, func(s backoff.State) bool {
+		return s.Attempts >= 5 || s.Elapsed+s.Interval > time.Minute
+	})
+
+The same rule can be written with the predefined conditions:
+
+	b = backoff.StopWhen(b, backoff.StopOnAny(
+		backoff.MaxAttempts(5),
+		backoff.MaxElapsed(time.Minute),
+	))
+
+Once it stopped, the wrapper keeps returning Stop until the next Reset, even
+if stop would return false for later intervals. stop is not called once the
+wrapped policy returned Stop.
+
+Note: Implementation is not thread-safe.
+*/
+func StopWhen(b BackOff, stop StopCondition) BackOff {
+	return &backOffStopWhen{delegate: b, stop: stop, start: time.Now()}
+}
+
+type backOffStopWhen struct {
+	delegate BackOff
+	stop     StopCondition
+	start    time.Time
+	attempts int
+	slept    time.Duration
+	stopped  bool
+}
+
+func (b *backOffStopWhen) NextBackOff() time.Duration {
+	if b.stopped {
+		return Stop
+	}
+	next := b.delegate.NextBackOff()
+	if next == Stop {
+		b.stopped = true
+		return Stop
+	}
+	b.attempts++
+	s := State{Attempts: b.attempts, Elapsed: time.Since(b.start), Interval: next, Slept: b.slept}
+	if b.stop(s) {
+		b.stopped = true
+		return Stop
+	}
+	b.slept = SaturatingAdd(b.slept, next)
+	return next
+}
+
+func (b *backOffStopWhen) Reset() {
+	b.start = time.Now()
+	b.attempts = 0
+	b.slept = 0
+	b.stopped = false
+	b.delegate.Reset()
+}
+
+func (b *backOffStopWhen) Unwrap() BackOff {
+	return b.delegate
+}
@@ -0,0 +1,56 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopWhen(t *testing.T) {
+	var states []State
+	b := StopWhen(NewConstantBackOff(time.Second), func(s State) bool {
+		states = append(states, s)
+		return s.Attempts >= 3
+	})
+
+	assertSequence(t, b, time.Second, time.Second, Stop)
+	if len(states) != 3 {
+		t.Fatalf("invalid number of states: %d", len(states))
+	}
+	for i, s := range states {
+		if s.Attempts != i+1 || s.Interval != time.Second {
+			t.Errorf("%d: invalid state: %+v", i, s)
+		}
+	}
+
+	b.Reset()
+	assertSequence(t, b, time.Second)
+}
+
+func TestStopWhenElapsed(t *testing.T) {
+	b := StopWhen(&ZeroBackOff{}, func(s State) bool {
+		return s.Elapsed > 10*time.Millisecond
+	})
+
+	assertSequence(t, b, 0)
+	time.Sleep(20 * time.Millisecond)
+	assertSequence(t, b, Stop)
+}
+
+func TestStopWhenStopped(t *testing.T) {
+	b := StopWhen(scripted(1), func(s State) bool {
+		if s.Interval == Stop {
+			t.Error("called with Stop")
+		}
+		return false
+	})
+	assertSequence(t, b, 1, Stop)
+}
+
+func TestStopWhenLatches(t *testing.T) {
+	// The condition is not monotonic: a shorter interval fits the budget.
+	b := StopWhen(scripted(4, 5, 1), BudgetExhausted(6))
+	assertSequence(t, b, 4, Stop, Stop)
+
+	b.Reset()
+	assertSequence(t, b, 4, Stop)
+}
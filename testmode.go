@@ -0,0 +1,51 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+var testMode struct {
+	sync.RWMutex
+	enabled bool
+	scale   float64
+}
+
+// SetTestMode enables or disables the test mode of the package. In test mode
+// ExponentialBackOff does not randomize intervals, and Retry and Ticker
+// sleep for the intervals multiplied by the factor set with
+// SetTestTimeScale, so that tests exercising real retry paths finish quickly
+// and deterministically.
+//
+// Test mode affects all policies in the process. It is meant to be enabled
+// from tests only, e.g. in TestMain.
+func SetTestMode(enabled bool) {
+	testMode.Lock()
+	testMode.enabled = enabled
+	testMode.Unlock()
+}
+
+// SetTestTimeScale sets the factor by which sleeps are multiplied in test
+// mode, e.g. 0.01 to sleep a hundredth of each interval. A factor of 0,
+// the default, does not change sleeps.
+func SetTestTimeScale(factor float64) {
+	testMode.Lock()
+	testMode.scale = factor
+	testMode.Unlock()
+}
+
+func testModeEnabled() bool {
+	testMode.RLock()
+	defer testMode.RUnlock()
+	return testMode.enabled
+}
+
+// sleepDuration returns how long to actually sleep for the interval d.
+func sleepDuration(d time.Duration) time.Duration {
+	testMode.RLock()
+	defer testMode.RUnlock()
+	if !testMode.enabled || testMode.scale <= 0 {
+		return d
+	}
+	return scaleDuration(d, testMode.scale)
+}
@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetTestMode(t *testing.T) {
+	SetTestMode(true)
+	SetTestTimeScale(0.001)
+	defer SetTestMode(false)
+	defer SetTestTimeScale(0)
+
+	exp := NewExponentialBackOff()
+	exp.InitialInterval = time.Second
+	exp.Multiplier = 2
+	exp.Reset()
+	for _, expected := range []time.Duration{1, 2, 4} {
+		assertEquals(t, expected*time.Second, exp.NextBackOff())
+	}
+
+	var i = 0
+	f := func() error {
+		i++
+		if i == 3 {
+			return nil
+		}
+		return errors.New("error")
+	}
+
+	// Sleeps 1ms and 2ms instead of 1s and 2s.
+	start := time.Now()
+	if err := Retry(f, exp); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleeps are not compressed: %v", elapsed)
+	}
+}
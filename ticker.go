@@ -1,9 +1,12 @@
 package backoff
 
 import (
+	"errors"
 	"runtime"
 	"sync"
 	"time"
+
+	"golang.org/x/net/context"
 )
 
 // Ticker holds a channel that delivers `ticks' of a clock at times reported by a BackOff.
@@ -11,11 +14,39 @@ import (
 // Ticks will continue to arrive when the previous operation is still running,
 // so operations that take a while to fail could run in quick succession.
 type Ticker struct {
-	C        <-chan time.Time
+	C <-chan time.Time
+	// Ticks delivers ticks together with their schedule. It is only set
+	// for tickers created with NewTickerWithHints, in which case C is nil.
+	Ticks    <-chan Tick
 	c        chan time.Time
+	ticks    chan Tick
+	waited   time.Duration
 	b        BackOffContext
 	stop     chan struct{}
 	stopOnce sync.Once
+	done     chan struct{}
+	mu       sync.Mutex
+	err      error
+	gate     *Gate
+}
+
+// Errors returned by Ticker.Err.
+var (
+	// ErrTickerStopped is returned when the ticker was stopped with Stop.
+	ErrTickerStopped = errors.New("backoff: ticker stopped")
+	// ErrBackOffStopped is returned when the BackOff returned Stop.
+	ErrBackOffStopped = errors.New("backoff: backoff policy stopped")
+)
+
+// Tick is a tick delivered on Ticker.Ticks.
+type Tick struct {
+	// Time is the time the tick was produced.
+	Time time.Time
+	// Waited is the delay waited before this tick. It is zero for the first tick.
+	Waited time.Duration
+	// Next is the delay until the following tick,
+	// or Stop if this is the last tick.
+	Next time.Duration
 }
 
 // NewTicker returns a new Ticker containing a channel that will send
@@ -25,13 +56,55 @@ type Ticker struct {
 // provided backoff policy (notably calling NextBackOff or Reset)
 // while the ticker is running.
 func NewTicker(b BackOff) *Ticker {
+	c := make(chan time.Time)
+	t := &Ticker{
+		C: c,
+		c: c,
+	}
+	return t.start(b)
+}
+
+// NewTickerWithContext is like NewTicker but the ticker stops when ctx is
+// canceled. The goroutine delivering ticks then exits even if the channel
+// is never drained.
+func NewTickerWithContext(ctx context.Context, b BackOff) *Ticker {
+	return NewTicker(WithContext(b, ctx))
+}
+
+// NewTickerWithHints is like NewTicker except that ticks are delivered on
+// the Ticks channel along with the delay that was just waited and the
+// upcoming delay chosen by the BackOff.
+//
+// Unlike NewTicker, which asks the BackOff for the next delay once a tick was
+// received, the delay is chosen before the tick is sent, since the tick
+// carries it. If the ticker is stopped while a tick is waiting to be
+// received, the BackOff has thus advanced once more than the ticks
+// delivered.
+func NewTickerWithHints(b BackOff) *Ticker {
+	ticks := make(chan Tick)
+	t := &Ticker{
+		Ticks: ticks,
+		ticks: ticks,
+	}
+	return t.start(b)
+}
+
+// NewTickerWithGate is like NewTicker but ticks are held while g is closed.
+// A tick that was due while the gate was closed is sent once it reopens.
+func NewTickerWithGate(b BackOff, g *Gate) *Ticker {
 	c := make(chan time.Time)
 	t := &Ticker{
 		C:    c,
 		c:    c,
-		b:    ensureContext(b),
-		stop: make(chan struct{}),
+		gate: g,
 	}
+	return t.start(b)
+}
+
+func (t *Ticker) start(b BackOff) *Ticker {
+	t.b = ensureContext(b)
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
 	t.b.Reset()
 	go t.run()
 	runtime.SetFinalizer(t, (*Ticker).Stop)
@@ -43,11 +116,74 @@ func (t *Ticker) Stop() {
 	t.stopOnce.Do(func() { close(t.stop) })
 }
 
+// Start stops the ticker when ctx is canceled. Tickers run from their
+// creation; Start only ties them to the lifecycle of ctx.
+func (t *Ticker) Start(ctx context.Context) {
+	stopOnCancel(ctx, t.Stop, t.done)
+}
+
+// Close stops the ticker and waits until its goroutine has exited.
+func (t *Ticker) Close() error {
+	t.Stop()
+	<-t.done
+	return nil
+}
+
+// Healthy reports whether the ticker is still running.
+func (t *Ticker) Healthy() bool {
+	return t.Err() == nil
+}
+
+// Done returns a channel that is closed once the ticker has stopped and its
+// goroutine has exited.
+func (t *Ticker) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err returns nil while the ticker is running. Once the channel is closed it
+// reports why: ErrTickerStopped if Stop was called, ErrBackOffStopped if the
+// BackOff returned Stop, or the error of the BackOff's context if it was
+// canceled.
+func (t *Ticker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Ticker) setErr(err error) {
+	t.mu.Lock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.mu.Unlock()
+}
+
+// stopped records that the BackOff returned Stop and stops the ticker.
+func (t *Ticker) stopped() {
+	if err := t.b.Context().Err(); err != nil {
+		t.setErr(err)
+	} else {
+		t.setErr(ErrBackOffStopped)
+	}
+	t.Stop()
+}
+
 func (t *Ticker) run() {
-	c := t.c
-	defer close(c)
+	c, ticks := t.c, t.ticks
+	defer func() {
+		t.setErr(ErrTickerStopped)
+		if ticks != nil {
+			close(ticks)
+		} else {
+			close(c)
+		}
+		close(t.done)
+	}()
 
 	// Ticker is guaranteed to tick at least once.
+	if !t.hold() {
+		return
+	}
 	afterC := t.send(time.Now())
 
 	for {
@@ -57,28 +193,87 @@ func (t *Ticker) run() {
 
 		select {
 		case tick := <-afterC:
+			if !t.hold() {
+				return
+			}
 			afterC = t.send(tick)
 		case <-t.stop:
-			t.c = nil // Prevent future ticks from being sent to the channel.
+			t.c, t.ticks = nil, nil // Prevent future ticks from being sent to the channel.
 			return
 		case <-t.b.Context().Done():
+			t.setErr(t.b.Context().Err())
 			return
 		}
 	}
 }
 
+// hold waits while the gate of the ticker is closed. It returns false if the
+// ticker was stopped meanwhile.
+func (t *Ticker) hold() bool {
+	if t.gate == nil {
+		return true
+	}
+	select {
+	case <-t.gate.opened():
+		return true
+	case <-t.stop:
+		return false
+	case <-t.b.Context().Done():
+		t.setErr(t.b.Context().Err())
+		return false
+	}
+}
+
 func (t *Ticker) send(tick time.Time) <-chan time.Time {
+	if t.ticks != nil {
+		return t.sendHint(tick)
+	}
+
 	select {
 	case t.c <- tick:
 	case <-t.stop:
 		return nil
+	case <-t.b.Context().Done():
+		t.setErr(t.b.Context().Err())
+		return nil
+	}
+
+	next := t.b.NextBackOff()
+	if next == Stop {
+		t.stopped()
+		return nil
 	}
 
+	return time.After(sleepDuration(next))
+}
+
+func (t *Ticker) sendHint(tick time.Time) <-chan time.Time {
+	// The tick carries the next delay, so it is computed before sending,
+	// but not for a ticker that is already stopped.
+	select {
+	case <-t.stop:
+		return nil
+	case <-t.b.Context().Done():
+		t.setErr(t.b.Context().Err())
+		return nil
+	default:
+	}
 	next := t.b.NextBackOff()
+
+	select {
+	case t.ticks <- Tick{Time: tick, Waited: t.waited, Next: next}:
+	case <-t.stop:
+		return nil
+	case <-t.b.Context().Done():
+		t.setErr(t.b.Context().Err())
+		return nil
+	}
+
 	if next == Stop {
-		t.Stop()
+		t.stopped()
 		return nil
 	}
 
-	return time.After(next)
+	t.waited = next
+	return time.After(sleepDuration(next))
 }
@@ -96,3 +96,79 @@ func TestTickerContext(t *testing.T) {
 		t.Errorf("invalid number of retries: %d", i)
 	}
 }
+
+func TestTickerWithHints(t *testing.T) {
+	b := WithMaxRetries(NewConstantBackOff(time.Millisecond), 2)
+	ticker := NewTickerWithHints(b)
+
+	var ticks []Tick
+	for tick := range ticker.Ticks {
+		ticks = append(ticks, tick)
+	}
+
+	expected := []struct{ waited, next time.Duration }{
+		{0, time.Millisecond},
+		{time.Millisecond, time.Millisecond},
+		{time.Millisecond, Stop},
+	}
+	if len(ticks) != len(expected) {
+		t.Fatalf("invalid number of ticks: %d", len(ticks))
+	}
+	for i, e := range expected {
+		if ticks[i].Waited != e.waited || ticks[i].Next != e.next {
+			t.Errorf("tick %d: waited %v next %v, expected waited %v next %v",
+				i, ticks[i].Waited, ticks[i].Next, e.waited, e.next)
+		}
+	}
+}
+
+func TestTickerErr(t *testing.T) {
+	ticker := NewTicker(WithMaxRetries(&ZeroBackOff{}, 1))
+	if err := ticker.Err(); err != nil {
+		t.Errorf("unexpected error while running: %v", err)
+	}
+	for _ = range ticker.C {
+	}
+	if err := ticker.Err(); err != ErrBackOffStopped {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ticker = NewTicker(NewConstantBackOff(time.Millisecond))
+	<-ticker.C
+	ticker.Stop()
+	for _ = range ticker.C {
+	}
+	if err := ticker.Err(); err != ErrTickerStopped {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker = NewTicker(WithContext(NewConstantBackOff(time.Millisecond), ctx))
+	<-ticker.C
+	cancel()
+	for _ = range ticker.C {
+	}
+	if err := ticker.Err(); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTickerWithContextUndrained(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := NewTickerWithContext(ctx, NewConstantBackOff(time.Millisecond))
+
+	// The channel is never drained.
+	cancel()
+
+	select {
+	case <-ticker.Done():
+	case <-time.After(time.Second):
+		t.Fatal("ticker goroutine is still running after cancellation")
+	}
+	if err := ticker.Err(); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, ok := <-ticker.C; ok {
+		t.Error("channel is not closed")
+	}
+}
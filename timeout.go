@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// WithTotalTimeout limits the retry loop to d of wall-clock time, covering
+// both the attempts and the sleeps between them. This is distinct from the
+// MaxElapsedTime of a policy, which only the policy knows about.
+//
+// When the budget runs out, the context passed to a ContextOperation is
+// canceled and the retry loop returns the last error. The loop also gives up
+// without sleeping if the next attempt could not start within the budget.
+func WithTotalTimeout(d time.Duration) RetryOption {
+	return func(o *retryOptions) { o.totalTimeout = d }
+}
+
+// Reason for returning from a retry loop that ran out of its total timeout,
+// or whose next attempt would start after it.
+const reasonTotalTimeout = "total timeout"
+
+// exceedsDeadline reports whether sleeping for next would go past the total
+// timeout of the retry loop.
+func (o *retryOptions) exceedsDeadline(next time.Duration) bool {
+	return !o.deadline.IsZero() && time.Now().Add(next).After(o.deadline)
+}
+
+// doneReason returns why ctx, derived from parent, is done: reasonTotalTimeout
+// if it reached the total timeout before parent was done, else its error.
+func (o *retryOptions) doneReason(parent, ctx context.Context) string {
+	if err := parent.Err(); err != nil {
+		return err.Error()
+	}
+	if !o.deadline.IsZero() && ctx.Err() == context.DeadlineExceeded {
+		return reasonTotalTimeout
+	}
+	return ctx.Err().Error()
+}
@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestWithTotalTimeout(t *testing.T) {
+	var i = 0
+	f := func(ctx context.Context) error {
+		i++
+		if i == 2 {
+			// The attempt is canceled when the budget runs out.
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return errors.New("error")
+	}
+
+	start := time.Now()
+	err := RetryContext(context.Background(), f, NewConstantBackOff(10*time.Millisecond), WithTotalTimeout(50*time.Millisecond))
+	if err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("attempt is not canceled: %v", elapsed)
+	}
+	if i != 2 {
+		t.Errorf("invalid number of retries: %d", i)
+	}
+}
+
+func TestWithTotalTimeoutSkipsLongSleep(t *testing.T) {
+	var i = 0
+	f := func() error {
+		i++
+		return errors.New("error")
+	}
+
+	start := time.Now()
+	err := Retry(f, NewConstantBackOff(time.Minute), WithTotalTimeout(time.Second))
+	if err == nil || i != 1 {
+		t.Errorf("unexpected error %v after %d attempts", err, i)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("retry loop slept past the budget: %v", elapsed)
+	}
+}
+
+func TestWithTotalTimeoutDuringSleep(t *testing.T) {
+	events := make(chan Event, 100)
+	f := func() error { return errors.New("error") }
+	// notify delays the sleep, so that the budget runs out during it.
+	notify := func(error, time.Duration) { time.Sleep(40 * time.Millisecond) }
+
+	for _, threshold := range []time.Duration{0, time.Second} {
+		err := RetryNotify(f, NewConstantBackOff(20*time.Millisecond), notify,
+			WithTotalTimeout(50*time.Millisecond), WithPrecisionSleep(threshold), WithEvents(events))
+		if err == nil || err.Error() != "error" {
+			t.Errorf("unexpected error: %v", err)
+		}
+		var last Event
+		for len(events) > 0 {
+			last = <-events
+		}
+		if last.Type != GaveUp || last.Reason != reasonTotalTimeout {
+			t.Errorf("invalid event with threshold %s: %+v", threshold, last)
+		}
+	}
+}
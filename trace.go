@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithTrace writes a human-readable trace of the retry loop to w: when each
+// attempt starts and how it ends, how long the loop sleeps before retrying,
+// and why it returns. It is meant for debugging and for command line tools
+// where logging and metrics are overkill.
+//
+// A trace looks like:
+//
+//	15:04:05.000 fetch: attempt 1
+//	15:04:05.012 fetch: attempt 1 failed: connection refused
+//	15:04:05.012 fetch: retrying in 500ms
+//	15:04:05.513 fetch: attempt 2
+//	15:04:05.520 fetch: attempt 2 succeeded
+//	15:04:05.520 fetch: succeeded after 2 attempts
+func WithTrace(w io.Writer) RetryOption {
+	return func(o *retryOptions) {
+		t := &tracer{w: w, o: o}
+		o.before = append(o.before, t.before)
+		o.after = append(o.after, t.after)
+		o.waits = append(o.waits, t.wait)
+		o.finishes = append(o.finishes, t.finish)
+	}
+}
+
+type tracer struct {
+	w io.Writer
+	o *retryOptions
+}
+
+func (t *tracer) printf(format string, args ...interface{}) {
+	prefix := time.Now().Format("15:04:05.000")
+	if t.o.name != "" {
+		prefix += " " + t.o.name
+	}
+	fmt.Fprintf(t.w, prefix+": "+format+"\n", args...)
+}
+
+func (t *tracer) before(a Attempt) {
+	t.printf("attempt %d", a.Number)
+}
+
+func (t *tracer) after(a Attempt, err error) {
+	if err == nil {
+		t.printf("attempt %d succeeded", a.Number)
+	} else {
+		t.printf("attempt %d failed: %s", a.Number, err)
+	}
+}
+
+func (t *tracer) wait(d time.Duration) {
+	t.printf("retrying in %s", d)
+}
+
+func (t *tracer) finish(a Attempt, err error, reason string) {
+	if err == nil {
+		t.printf("succeeded after %d attempts", a.Number)
+	} else {
+		t.printf("gave up after %d attempts (%s): %s", a.Number, reason, err)
+	}
+}
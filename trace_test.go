@@ -0,0 +1,35 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestWithTrace(t *testing.T) {
+	var buf bytes.Buffer
+	f := func() error { return errors.New("connection refused") }
+	b := WithMaxRetries(NewConstantBackOff(time.Millisecond), 1)
+
+	Retry(f, b, WithTrace(&buf), WithOperationName("fetch"))
+
+	expected := []string{
+		"fetch: attempt 1",
+		"fetch: attempt 1 failed: connection refused",
+		"fetch: retrying in 1ms",
+		"fetch: attempt 2",
+		"fetch: attempt 2 failed: connection refused",
+		"fetch: gave up after 2 attempts (backoff stopped): connection refused",
+	}
+	lines := regexp.MustCompile(`(?m)^\d\d:\d\d:\d\d\.\d\d\d (.*)$`).FindAllStringSubmatch(buf.String(), -1)
+	if len(lines) != len(expected) {
+		t.Fatalf("invalid trace:\n%s", buf.String())
+	}
+	for i, e := range expected {
+		if lines[i][1] != e {
+			t.Errorf("line %d: got %q, expected %q", i, lines[i][1], e)
+		}
+	}
+}
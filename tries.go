@@ -1,6 +1,9 @@
 package backoff
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 /*
 WithMaxRetries creates a wrapper around another BackOff, which will
@@ -33,3 +36,11 @@ func (b *backOffTries) Reset() {
 	b.numTries = 0
 	b.delegate.Reset()
 }
+
+func (b *backOffTries) String() string {
+	return fmt.Sprintf("WithMaxRetries{tries=%d/%d}", b.numTries, b.maxTries)
+}
+
+func (b *backOffTries) Unwrap() BackOff {
+	return b.delegate
+}
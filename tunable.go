@@ -0,0 +1,109 @@
+package backoff
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tunable is a Policy whose underlying policy can be swapped at runtime,
+// e.g. by a configuration watcher, without restarting the retry loops and
+// tickers using it. It is safe for concurrent use.
+//
+// The BackOff instances created by a Tunable switch to the current policy
+// on their next call to Reset or NextBackOff after a swap. A switch starts
+// the schedule of the new policy from the beginning.
+type Tunable struct {
+	// version is accessed atomically and first so that it is 64-bit aligned.
+	version uint64
+
+	mu        sync.RWMutex
+	policy    Policy
+	listeners []func(Policy)
+}
+
+// NewTunable returns a Tunable using policy until it is swapped. It panics
+// if policy is nil.
+func NewTunable(policy Policy) *Tunable {
+	if policy == nil {
+		panic("backoff: NewTunable policy is nil")
+	}
+	return &Tunable{policy: policy}
+}
+
+// Set swaps the policy of t and calls the functions registered with
+// OnChange with it. It panics if policy is nil.
+func (t *Tunable) Set(policy Policy) {
+	if policy == nil {
+		panic("backoff: Tunable policy is nil")
+	}
+	t.mu.Lock()
+	t.policy = policy
+	atomic.AddUint64(&t.version, 1)
+	listeners := t.listeners
+	t.mu.Unlock()
+
+	for _, f := range listeners {
+		f(policy)
+	}
+}
+
+// Policy returns the current policy of t.
+func (t *Tunable) Policy() Policy {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.policy
+}
+
+// OnChange registers f to be called with the new policy every time it is
+// swapped with Set. The functions are called in the order they were
+// registered, by the goroutine calling Set.
+func (t *Tunable) OnChange(f func(p Policy)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.listeners = append(t.listeners[:len(t.listeners):len(t.listeners)], f)
+}
+
+// New returns a BackOff following the current policy of t, then the
+// policies it is swapped to.
+func (t *Tunable) New() BackOff {
+	b := &backOffTunable{tunable: t}
+	b.update()
+	return b
+}
+
+// current returns the policy of t and its version.
+func (t *Tunable) current() (Policy, uint64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.policy, atomic.LoadUint64(&t.version)
+}
+
+type backOffTunable struct {
+	tunable  *Tunable
+	version  uint64
+	delegate BackOff
+}
+
+// update switches to the current policy of the Tunable if it was swapped.
+func (b *backOffTunable) update() {
+	if b.delegate != nil && atomic.LoadUint64(&b.tunable.version) == b.version {
+		return
+	}
+	p, version := b.tunable.current()
+	b.delegate, b.version = p.New(), version
+}
+
+func (b *backOffTunable) NextBackOff() time.Duration {
+	b.update()
+	return b.delegate.NextBackOff()
+}
+
+func (b *backOffTunable) Reset() {
+	b.update()
+	b.delegate.Reset()
+}
+
+func (b *backOffTunable) Unwrap() BackOff {
+	return b.delegate
+}
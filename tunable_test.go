@@ -0,0 +1,74 @@
+package backoff
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func constantPolicy(d time.Duration) Policy {
+	return PolicyFunc(func() BackOff { return NewConstantBackOff(d) })
+}
+
+func TestTunable(t *testing.T) {
+	tunable := NewTunable(constantPolicy(time.Second))
+	var changes []Policy
+	tunable.OnChange(func(p Policy) { changes = append(changes, p) })
+
+	b := tunable.New()
+	assertSequence(t, b, time.Second, time.Second)
+
+	p := constantPolicy(time.Minute)
+	tunable.Set(p)
+	assertSequence(t, b, time.Minute)
+	b.Reset()
+	assertSequence(t, b, time.Minute)
+	assertSequence(t, tunable.New(), time.Minute)
+
+	if len(changes) != 1 {
+		t.Errorf("invalid number of changes: %d", len(changes))
+	}
+	if tunable.Policy() == nil {
+		t.Error("no policy")
+	}
+}
+
+func TestTunableRestartsSchedule(t *testing.T) {
+	tunable := NewTunable(PolicyFunc(func() BackOff { return scripted(1, 2, 3) }))
+	b := tunable.New()
+	assertSequence(t, b, 1, 2)
+
+	tunable.Set(PolicyFunc(func() BackOff { return scripted(4, 5) }))
+	assertSequence(t, b, 4, 5, Stop)
+}
+
+func TestTunableConcurrent(t *testing.T) {
+	tunable := NewTunable(constantPolicy(time.Second))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := tunable.New()
+			for j := 0; j < 1000; j++ {
+				if next := b.NextBackOff(); next != time.Second && next != time.Minute {
+					t.Errorf("invalid interval: %v", next)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		tunable.Set(constantPolicy(time.Minute))
+	}
+	wg.Wait()
+}
+
+func TestTunablePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	NewTunable(constantPolicy(time.Second)).Set(nil)
+}
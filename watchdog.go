@@ -0,0 +1,38 @@
+package backoff
+
+import (
+	"runtime"
+	"time"
+)
+
+// WithWatchdog calls stuck when an attempt is still running after limit, for
+// example ten times the attempt timeout, to help diagnosing operations that
+// hang rather than fail. stuck receives the attempt and a dump of the stacks
+// of all goroutines, taken when the limit was reached. It is called from
+// another goroutine, at most once per attempt, and does not interrupt the
+// attempt.
+func WithWatchdog(limit time.Duration, stuck func(a Attempt, stacks []byte)) RetryOption {
+	return func(o *retryOptions) {
+		var timer *time.Timer
+		o.before = append(o.before, func(a Attempt) {
+			timer = time.AfterFunc(limit, func() { stuck(a, allStacks()) })
+		})
+		o.after = append(o.after, func(Attempt, error) {
+			if timer != nil {
+				timer.Stop()
+			}
+		})
+	}
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
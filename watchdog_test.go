@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithWatchdog(t *testing.T) {
+	type report struct {
+		a      Attempt
+		stacks []byte
+	}
+	reports := make(chan report, 10)
+	stuck := func(a Attempt, stacks []byte) { reports <- report{a, stacks} }
+
+	f := func(a Attempt) error {
+		if a.Number == 2 {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}
+		return errors.New("error")
+	}
+	if err := RetryAttempt(f, &ZeroBackOff{}, WithWatchdog(20*time.Millisecond, stuck)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	r := <-reports
+	if r.a.Number != 2 {
+		t.Errorf("invalid attempt: %d", r.a.Number)
+	}
+	if !bytes.Contains(r.stacks, []byte("TestWithWatchdog")) {
+		t.Error("stacks do not contain the stuck attempt")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if len(reports) != 0 {
+		t.Errorf("unexpected reports: %d", len(reports))
+	}
+}
@@ -0,0 +1,88 @@
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Weighted is a schedule of a policy returned by WeightedOf, chosen with a
+// probability proportional to Weight.
+type Weighted struct {
+	Weight  float64
+	BackOff BackOff
+}
+
+/*
+WeightedOf returns a BackOff that samples one of the given schedules at each
+step and returns its next interval. It is useful to probe a recovering
+service now and then while mostly backing off, e.g.
+
+	b := backoff.WeightedOf(
+		backoff.Weighted{Weight: 0.9, BackOff: backoff.NewExponentialBackOff()},
+		backoff.Weighted{Weight: 0.1, BackOff: backoff.NewConstantBackOff(time.Minute)},
+	)
+
+Only the sampled schedule advances, and the policy stops as soon as a sampled
+schedule returns Stop. Reset resets all the schedules.
+
+WeightedOf panics if no schedule has a positive weight.
+
+Note: Implementation is not thread-safe.
+*/
+func WeightedOf(schedules ...Weighted) BackOff {
+	var total float64
+	for _, s := range schedules {
+		if s.Weight > 0 {
+			total += s.Weight
+		}
+	}
+	if total <= 0 {
+		panic("backoff: no schedule with a positive weight")
+	}
+	return &backOffWeighted{schedules: schedules, total: total}
+}
+
+type backOffWeighted struct {
+	schedules []Weighted
+	total     float64
+	random    *rand.Rand
+	stopped   bool
+}
+
+func (b *backOffWeighted) NextBackOff() time.Duration {
+	if b.stopped {
+		return Stop
+	}
+	if b.random == nil {
+		b.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	next := b.pick(b.random.Float64() * b.total).NextBackOff()
+	if next == Stop {
+		b.stopped = true
+	}
+	return next
+}
+
+// pick returns the schedule at x in [0, total).
+func (b *backOffWeighted) pick(x float64) BackOff {
+	var last BackOff
+	for _, s := range b.schedules {
+		if s.Weight <= 0 {
+			continue
+		}
+		if x < s.Weight {
+			return s.BackOff
+		}
+		x -= s.Weight
+		last = s.BackOff
+	}
+	// Rounding errors may leave x just below total.
+	return last
+}
+
+func (b *backOffWeighted) Reset() {
+	b.stopped = false
+	for _, s := range b.schedules {
+		s.BackOff.Reset()
+	}
+}
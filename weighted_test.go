@@ -0,0 +1,65 @@
+package backoff
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestWeightedOf(t *testing.T) {
+	short := NewConstantBackOff(time.Millisecond)
+	long := NewConstantBackOff(time.Minute)
+	b := WeightedOf(
+		Weighted{Weight: 9, BackOff: short},
+		Weighted{Weight: 0, BackOff: &StopBackOff{}},
+		Weighted{Weight: 1, BackOff: long},
+	)
+	b.(*backOffWeighted).random = rand.New(rand.NewSource(1))
+
+	counts := make(map[time.Duration]int)
+	for i := 0; i < 1000; i++ {
+		counts[b.NextBackOff()]++
+	}
+	if len(counts) != 2 {
+		t.Fatalf("unexpected intervals: %v", counts)
+	}
+	if n := counts[time.Minute]; n < 50 || n > 150 {
+		t.Errorf("long schedule was sampled %d times out of 1000", n)
+	}
+}
+
+func TestWeightedOfAdvancesSampledSchedule(t *testing.T) {
+	a := scripted(1, 2, 3)
+	b := WeightedOf(Weighted{Weight: 1, BackOff: a})
+
+	assertSequence(t, b, 1, 2, 3, Stop)
+	b.Reset()
+	assertSequence(t, b, 1)
+}
+
+func TestWeightedOfStops(t *testing.T) {
+	b := WeightedOf(
+		Weighted{Weight: 1, BackOff: &StopBackOff{}},
+		Weighted{Weight: 1, BackOff: &ZeroBackOff{}},
+	)
+	for b.NextBackOff() != Stop {
+	}
+	// The policy keeps stopping even if another schedule is sampled.
+	for i := 0; i < 10; i++ {
+		assertSequence(t, b, Stop)
+	}
+
+	b = WeightedOf(Weighted{Weight: 1, BackOff: scripted(1)})
+	assertSequence(t, b, 1, Stop)
+	b.Reset()
+	assertSequence(t, b, 1)
+}
+
+func TestWeightedOfPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	WeightedOf(Weighted{Weight: 0, BackOff: &ZeroBackOff{}})
+}
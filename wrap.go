@@ -0,0 +1,61 @@
+package backoff
+
+import "reflect"
+
+// Wrapper is implemented by BackOff decorators, such as the ones returned by
+// WithContext and WithMaxRetries, to give access to the policy they wrap.
+// Decorators should implement it so that optional interfaces of the wrapped
+// policy can still be discovered with As.
+type Wrapper interface {
+	// Unwrap returns the wrapped policy.
+	Unwrap() BackOff
+}
+
+// UnwrapAll returns the chain of policies wrapped by b, starting with b
+// itself and ending with the innermost policy.
+func UnwrapAll(b BackOff) []BackOff {
+	var chain []BackOff
+	for b != nil {
+		chain = append(chain, b)
+		w, ok := b.(Wrapper)
+		if !ok {
+			break
+		}
+		b = w.Unwrap()
+	}
+	return chain
+}
+
+// As finds the first policy in the chain of b that is assignable to the
+// value pointed to by target, and if so, sets target to that policy and
+// returns true. target must be a non-nil pointer to an interface type or to
+// a type implementing BackOff, otherwise As panics.
+//
+// For example, find the ExponentialBackOff wrapped by decorators:
+//
+//	var exp *backoff.ExponentialBackOff
+//	if backoff.As(b, &exp) {
+//		log.Println(exp.GetElapsedTime())
+//	}
+func As(b BackOff, target interface{}) bool {
+	if target == nil {
+		panic("backoff: target cannot be nil")
+	}
+	val := reflect.ValueOf(target)
+	typ := val.Type()
+	if typ.Kind() != reflect.Ptr || val.IsNil() {
+		panic("backoff: target must be a non-nil pointer")
+	}
+	targetType := typ.Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(reflect.TypeOf((*BackOff)(nil)).Elem()) {
+		panic("backoff: *target must be interface or implement BackOff")
+	}
+
+	for _, p := range UnwrapAll(b) {
+		if reflect.TypeOf(p).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(p))
+			return true
+		}
+	}
+	return false
+}
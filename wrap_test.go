@@ -0,0 +1,50 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestUnwrapAll(t *testing.T) {
+	exp := NewExponentialBackOff()
+	tries := WithMaxRetries(exp, 3)
+	b := WithContext(tries, context.Background())
+
+	chain := UnwrapAll(b)
+	if len(chain) != 3 || chain[0] != b || chain[1] != tries || chain[2] != exp {
+		t.Errorf("invalid chain: %v", chain)
+	}
+}
+
+type elapsedTimer interface {
+	GetElapsedTime() time.Duration
+}
+
+func TestAs(t *testing.T) {
+	exp := NewExponentialBackOff()
+	b := WithContext(WithMaxRetries(exp, 3), context.Background())
+
+	var found *ExponentialBackOff
+	if !As(b, &found) || found != exp {
+		t.Error("ExponentialBackOff is not found")
+	}
+
+	var et elapsedTimer
+	if !As(b, &et) || et != exp {
+		t.Error("optional interface is not found")
+	}
+
+	var constant *ConstantBackOff
+	if As(b, &constant) {
+		t.Error("unexpected ConstantBackOff")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid target")
+		}
+	}()
+	As(b, found)
+}
@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"runtime"
+	"time"
+)
+
+// WithYield makes the retry loop give up the processor every n retries, so
+// that high-frequency retries of in-memory operations with near-zero delays,
+// e.g. with ZeroBackOff or WithPrecisionSleep, do not starve other
+// goroutines. Every n-th delay is extended to at least pause; a zero pause
+// calls runtime.Gosched instead, which is enough to let other goroutines
+// run without slowing the loop down.
+//
+// WithYield panics if n is not positive.
+func WithYield(n int, pause time.Duration) RetryOption {
+	if n <= 0 {
+		panic("backoff: yield batch size must be positive")
+	}
+	return func(o *retryOptions) {
+		o.yieldEvery = n
+		o.yieldPause = pause
+	}
+}
+
+// yield returns the duration of the sleep before the next retry, extended
+// if the loop must yield.
+func (o *retryOptions) yield(sleep time.Duration) time.Duration {
+	if o.yieldEvery == 0 {
+		return sleep
+	}
+	o.retries++
+	if o.retries%o.yieldEvery != 0 {
+		return sleep
+	}
+	if o.yieldPause <= 0 {
+		runtime.Gosched()
+	} else if sleep < o.yieldPause {
+		sleep = o.yieldPause
+	}
+	return sleep
+}
@@ -0,0 +1,56 @@
+package backoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithYield(t *testing.T) {
+	attempts := 0
+	f := func() error {
+		attempts++
+		if attempts < 7 {
+			return errors.New("error")
+		}
+		return nil
+	}
+
+	start := time.Now()
+	err := Retry(f, &ZeroBackOff{}, WithYield(3, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// The 3rd and 6th retries are delayed.
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("retries were not delayed: %v", elapsed)
+	}
+}
+
+func TestWithYieldPause(t *testing.T) {
+	cases := []struct {
+		pause   time.Duration
+		in, out []time.Duration
+	}{
+		// Yielding with runtime.Gosched does not change the delays.
+		{0, []time.Duration{0, 0, time.Second, time.Second}, []time.Duration{0, 0, time.Second, time.Second}},
+		{time.Millisecond, []time.Duration{0, 0, 0, time.Second}, []time.Duration{0, time.Millisecond, 0, time.Second}},
+	}
+	for _, c := range cases {
+		o := newRetryOptions([]RetryOption{WithYield(2, c.pause)})
+		for i, in := range c.in {
+			if out := o.yield(in); out != c.out[i] {
+				t.Errorf("pause %v, retry %d: got %v, want %v", c.pause, i+1, out, c.out[i])
+			}
+		}
+	}
+}
+
+func TestWithYieldPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	WithYield(0, 0)
+}